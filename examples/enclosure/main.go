@@ -0,0 +1,56 @@
+//-----------------------------------------------------------------------------
+/*
+
+Demonstration for a PCB Enclosure
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import . "github.com/deadsy/sdfx/sdf"
+
+//-----------------------------------------------------------------------------
+
+func enclosure() {
+
+	// 60 x 40 mm board with M2.5 mounting holes at the corners
+	board := Box2D(V2{60.0, 40.0}, 2.0)
+	holes := []V2{{-26.5, -16.5}, {-26.5, 16.5}, {26.5, -16.5}, {26.5, 16.5}}
+
+	ep := EnclosureParms{
+		Board: board,
+		Holes: holes,
+		Standoff: StandoffParms{
+			PillarDiameter: 6.0,
+			HoleDiameter:   2.2, // self tapping M2.5
+			NumberWebs:     4,
+			WebHeight:      4.0,
+			WebDiameter:    12.0,
+			WebWidth:       1.5,
+		},
+		Connectors: []PCBConnector{
+			{Connector: "usb_c", Edge: "-x", Position: 0},
+			{Connector: "barrel_5.5x2.1", Edge: "+x", Position: -8.0},
+			{Connector: "rj45", Edge: "+y", Position: 10.0},
+		},
+		BoardHeight: 6.0,  // underside of the board above the floor
+		Height:      25.0, // inside height
+		Wall:        2.0,
+		Clearance:   1.0,
+		Rounding:    4.0,
+	}
+
+	parts := Enclosure3D(&ep)
+
+	RenderSTL(parts[0], 300, "base.stl")
+	RenderSTL(parts[1], 300, "lid.stl")
+}
+
+//-----------------------------------------------------------------------------
+
+func main() {
+	enclosure()
+}
+
+//-----------------------------------------------------------------------------
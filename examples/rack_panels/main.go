@@ -0,0 +1,55 @@
+//-----------------------------------------------------------------------------
+/*
+
+Demonstration for Eurorack and 19 inch Rack Panels
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import . "github.com/deadsy/sdfx/sdf"
+
+//-----------------------------------------------------------------------------
+
+// 3U 8HP Eurorack module: 2 pots and 4 jacks
+func eurorack() {
+	x := 0.25 * EurorackWidth(8)
+	ep := EurorackParms{
+		Format:     "3U",
+		HP:         8,
+		SlotLength: 2.0,
+		Components: []PanelComponent{
+			{Component: "pot_9mm", Position: V2{0, 35.0}},
+			{Component: "pot_9mm", Position: V2{0, 15.0}},
+			{Component: "jack_3.5", Position: V2{-x, -20.0}},
+			{Component: "jack_3.5", Position: V2{x, -20.0}},
+			{Component: "jack_3.5", Position: V2{-x, -40.0}},
+			{Component: "jack_3.5", Position: V2{x, -40.0}},
+		},
+	}
+	RenderDXF(EurorackPanel2D(&ep), 400, "eurorack.dxf")
+}
+
+// 1U rack panel: a row of 1/4 inch jacks
+func rack() {
+	var jacks []PanelComponent
+	for i := 0; i < 8; i++ {
+		jacks = append(jacks, PanelComponent{Component: "jack_6.35", Position: V2{float64(i-4)*25.0 + 12.5, 0}})
+	}
+	rp := RackParms{
+		U:          1,
+		SlotLength: 3.0,
+		Components: jacks,
+	}
+	RenderDXF(RackPanel2D(&rp), 600, "rack.dxf")
+}
+
+//-----------------------------------------------------------------------------
+
+func main() {
+	eurorack()
+	rack()
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,290 @@
+//-----------------------------------------------------------------------------
+/*
+
+Bearings
+
+A database of common deep groove ball bearings and linear ball bearings
+with helpers for the housing seats and shaft seats.
+
+Fits:
+
+"press": the housing bore is smaller (or the shaft is larger) than the
+bearing by the allowance.
+
+"slip": the housing bore is larger (or the shaft is smaller) than the
+bearing by the allowance.
+
+The allowance is on the diameter. For 3d printed parts it depends on the
+printer, 0.1 to 0.2 mm is typical.
+
+Shoulders:
+
+The housing and shaft shoulders support the outer and inner rings of a
+ball bearing without touching the seals or the other ring.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+//-----------------------------------------------------------------------------
+// Bearing Database - lookup standard bearings by name
+
+type BearingParameters struct {
+	Name            string  // name of bearing
+	Kind            string  // "ball" (deep groove) or "linear"
+	Bore            float64 // inner diameter
+	OuterDiameter   float64 // outer diameter
+	Width           float64 // width (or length for a linear bearing)
+	ShaftShoulder   float64 // maximum shaft shoulder diameter
+	HousingShoulder float64 // minimum housing shoulder diameter
+}
+
+type BearingDatabase map[string]*BearingParameters
+
+var bearing_db = Init_BearingLookup()
+
+// BallAdd adds a deep groove ball bearing to the bearing database (dimensions in mm).
+func (m BearingDatabase) BallAdd(
+	name string, // bearing name
+	bore float64, // inner diameter
+	od float64, // outer diameter
+	width float64, // width
+) {
+	b := BearingParameters{}
+	b.Name = name
+	b.Kind = "ball"
+	b.Bore = bore
+	b.OuterDiameter = od
+	b.Width = width
+	// clear of the seals and the other ring
+	b.ShaftShoulder = bore + 0.2*(od-bore)
+	b.HousingShoulder = od - 0.2*(od-bore)
+	m[name] = &b
+}
+
+// LinearAdd adds a linear ball bearing to the bearing database (dimensions in mm).
+func (m BearingDatabase) LinearAdd(
+	name string, // bearing name
+	bore float64, // inner diameter
+	od float64, // outer diameter
+	length float64, // length
+) {
+	b := BearingParameters{}
+	b.Name = name
+	b.Kind = "linear"
+	b.Bore = bore
+	b.OuterDiameter = od
+	b.Width = length
+	b.HousingShoulder = od - 0.2*(od-bore)
+	m[name] = &b
+}
+
+func Init_BearingLookup() BearingDatabase {
+	m := make(BearingDatabase)
+	// miniature
+	m.BallAdd("603", 3, 9, 5)
+	m.BallAdd("604", 4, 12, 4)
+	m.BallAdd("605", 5, 14, 5)
+	m.BallAdd("606", 6, 17, 6)
+	m.BallAdd("607", 7, 19, 6)
+	m.BallAdd("608", 8, 22, 7)
+	m.BallAdd("609", 9, 24, 7)
+	m.BallAdd("623", 3, 10, 4)
+	m.BallAdd("624", 4, 13, 5)
+	m.BallAdd("625", 5, 16, 5)
+	m.BallAdd("626", 6, 19, 6)
+	m.BallAdd("627", 7, 22, 7)
+	m.BallAdd("628", 8, 24, 8)
+	m.BallAdd("629", 9, 26, 8)
+	m.BallAdd("688", 8, 16, 5)
+	// thin section
+	m.BallAdd("6800", 10, 19, 5)
+	m.BallAdd("6801", 12, 21, 5)
+	m.BallAdd("6802", 15, 24, 5)
+	m.BallAdd("6803", 17, 26, 5)
+	m.BallAdd("6804", 20, 32, 7)
+	m.BallAdd("6805", 25, 37, 7)
+	// extra light
+	m.BallAdd("6000", 10, 26, 8)
+	m.BallAdd("6001", 12, 28, 8)
+	m.BallAdd("6002", 15, 32, 9)
+	m.BallAdd("6003", 17, 35, 10)
+	m.BallAdd("6004", 20, 42, 12)
+	m.BallAdd("6005", 25, 47, 12)
+	m.BallAdd("6006", 30, 55, 13)
+	// light
+	m.BallAdd("6200", 10, 30, 9)
+	m.BallAdd("6201", 12, 32, 10)
+	m.BallAdd("6202", 15, 35, 11)
+	m.BallAdd("6203", 17, 40, 12)
+	m.BallAdd("6204", 20, 47, 14)
+	m.BallAdd("6205", 25, 52, 15)
+	m.BallAdd("6206", 30, 62, 16)
+	// medium
+	m.BallAdd("6300", 10, 35, 11)
+	m.BallAdd("6301", 12, 37, 12)
+	m.BallAdd("6302", 15, 42, 13)
+	m.BallAdd("6303", 17, 47, 14)
+	m.BallAdd("6304", 20, 52, 15)
+	// linear
+	m.LinearAdd("LM3UU", 3, 7, 10)
+	m.LinearAdd("LM4UU", 4, 8, 12)
+	m.LinearAdd("LM5UU", 5, 10, 15)
+	m.LinearAdd("LM6UU", 6, 12, 19)
+	m.LinearAdd("LM8UU", 8, 15, 24)
+	m.LinearAdd("LM10UU", 10, 19, 29)
+	m.LinearAdd("LM12UU", 12, 21, 30)
+	m.LinearAdd("LM13UU", 13, 23, 32)
+	m.LinearAdd("LM16UU", 16, 28, 37)
+	m.LinearAdd("LM20UU", 20, 32, 42)
+	m.LinearAdd("LM25UU", 25, 40, 59)
+	m.LinearAdd("LM30UU", 30, 45, 64)
+	m.LinearAdd("LM8LUU", 8, 15, 45)
+	m.LinearAdd("LM10LUU", 10, 19, 55)
+	m.LinearAdd("LM12LUU", 12, 21, 57)
+	return m
+}
+
+// lookup the parameters for a bearing by name
+func BearingLookupErr(name string) (*BearingParameters, error) {
+	b, ok := bearing_db[name]
+	if !ok {
+		return nil, param_error("BearingLookup", "name", name, "bearing name not found")
+	}
+	return b, nil
+}
+
+// lookup the parameters for a bearing by name (panic on error)
+func BearingLookup(name string) *BearingParameters {
+	b, err := BearingLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// bearing_fit returns the diameter change for a fit (for a housing).
+func bearing_fit(fn, fit string, allowance float64) (float64, error) {
+	if allowance < 0 {
+		return 0, param_error(fn, "Allowance", allowance, "must be >= 0")
+	}
+	switch fit {
+	case "press":
+		return -allowance, nil
+	case "slip":
+		return allowance, nil
+	}
+	return 0, param_error(fn, "Fit", fit, "must be press or slip")
+}
+
+//-----------------------------------------------------------------------------
+// Housing Seats
+
+type BearingSeatParms struct {
+	Bearing   string  // name of bearing (see BearingLookup)
+	Fit       string  // "press" or "slip"
+	Allowance float64 // fit allowance on the diameter
+	Depth     float64 // seat depth (0 for the bearing width)
+	Length    float64 // total length including the shoulder hole (0 for no shoulder hole)
+}
+
+// NewBearingSeat3D returns the housing cut-out for a bearing.
+// The seat opens on the z = 0 plane and extends along -z. The housing
+// shoulder hole continues below the seat, so the outer ring sits on the
+// shoulder.
+func NewBearingSeat3D(k *BearingSeatParms) (SDF3, error) {
+	b, err := BearingLookupErr(k.Bearing)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := bearing_fit("BearingSeat3D", k.Fit, k.Allowance)
+	if err != nil {
+		return nil, err
+	}
+	depth := k.Depth
+	if depth == 0 {
+		depth = b.Width
+	}
+	if depth < 0 {
+		return nil, param_error("BearingSeat3D", "Depth", k.Depth, "must be >= 0")
+	}
+	if k.Length != 0 && k.Length <= depth {
+		return nil, param_error("BearingSeat3D", "Length", k.Length, "must be 0 or > seat depth")
+	}
+	s := Cylinder3D(depth, 0.5*(b.OuterDiameter+delta), 0)
+	s = Transform3D(s, Translate3d(V3{0, 0, -0.5 * depth}))
+	if k.Length > 0 {
+		hole := Cylinder3D(k.Length, 0.5*b.HousingShoulder, 0)
+		hole = Transform3D(hole, Translate3d(V3{0, 0, -0.5 * k.Length}))
+		s = Union3D(s, hole)
+	}
+	return s, nil
+}
+
+// BearingSeat3D returns the housing cut-out for a bearing (panic on error).
+func BearingSeat3D(k *BearingSeatParms) SDF3 {
+	s, err := NewBearingSeat3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+// Shaft Seats
+
+type BearingShaftParms struct {
+	Bearing        string  // name of bearing (see BearingLookup)
+	Fit            string  // "press" or "slip"
+	Allowance      float64 // fit allowance on the diameter
+	Length         float64 // length of the bearing seat (0 for the bearing width)
+	ShoulderLength float64 // length of the shaft shoulder (0 for no shoulder)
+}
+
+// NewBearingShaft3D returns a shaft with a bearing seat and shoulder.
+// The bearing seat starts on the z = 0 plane and extends along +z, the
+// shoulder extends along -z.
+func NewBearingShaft3D(k *BearingShaftParms) (SDF3, error) {
+	b, err := BearingLookupErr(k.Bearing)
+	if err != nil {
+		return nil, err
+	}
+	delta, err := bearing_fit("BearingShaft3D", k.Fit, k.Allowance)
+	if err != nil {
+		return nil, err
+	}
+	l := k.Length
+	if l == 0 {
+		l = b.Width
+	}
+	if l < 0 {
+		return nil, param_error("BearingShaft3D", "Length", k.Length, "must be >= 0")
+	}
+	if k.ShoulderLength < 0 {
+		return nil, param_error("BearingShaft3D", "ShoulderLength", k.ShoulderLength, "must be >= 0")
+	}
+	if k.ShoulderLength > 0 && b.Kind != "ball" {
+		return nil, param_error("BearingShaft3D", "ShoulderLength", k.ShoulderLength, "no shaft shoulder for "+b.Kind+" bearings")
+	}
+	// the shaft is the inverse of the housing fit
+	s := Cylinder3D(l, 0.5*(b.Bore-delta), 0)
+	s = Transform3D(s, Translate3d(V3{0, 0, 0.5 * l}))
+	if k.ShoulderLength > 0 {
+		shoulder := Cylinder3D(k.ShoulderLength, 0.5*b.ShaftShoulder, 0)
+		shoulder = Transform3D(shoulder, Translate3d(V3{0, 0, -0.5 * k.ShoulderLength}))
+		s = Union3D(s, shoulder)
+	}
+	return s, nil
+}
+
+// BearingShaft3D returns a shaft with a bearing seat and shoulder (panic on error).
+func BearingShaft3D(k *BearingShaftParms) SDF3 {
+	s, err := NewBearingShaft3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,242 @@
+//-----------------------------------------------------------------------------
+/*
+
+Nuts, Bolts and Washers
+
+Parts are built from the thread database (see ThreadLookup) so the same code
+works for metric and inch threads. Dimensions are in the units of the thread.
+
+Head proportions (d = nominal diameter):
+
+hex: hex flat to flat from the thread database (see Hex_Radius, Hex_Height)
+socket: socket head cap screw, head diameter 1.6d, height d, socket 0.8d
+button: button head, head diameter 1.75d, height 0.55d, socket 0.625d
+countersunk: 90 degree countersunk head, head diameter 2d, socket 0.625d
+knurl: knurled cylindrical head with the hex head dimensions
+
+For 3d printed parts the tolerance is subtracted from the radius of
+external threads and added to the radius of internal threads. Typically
+0.0 to 0.4 mm, it depends on the printer.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+
+// thread_profile returns the 2d thread profile for a thread.
+func thread_profile(t *ThreadParameters, radius float64, mode string) SDF2 {
+	switch t.Form {
+	case "tr":
+		return TrapezoidalThread(radius, t.Pitch)
+	case "whitworth":
+		return WhitworthThread(radius, t.Pitch)
+	}
+	return ISOThread(radius, t.Pitch, mode)
+}
+
+// hex_socket returns a hex socket of given flat to flat size and depth.
+// It is centered on the z = 0 plane, so it cuts depth into a face placed there.
+func hex_socket(f2f, depth float64) SDF3 {
+	r := f2f / (2.0 * math.Cos(DtoR(30)))
+	return Extrude3D(Polygon2D(Nagon(6, r)), 2.0*depth)
+}
+
+//-----------------------------------------------------------------------------
+// Bolts
+
+type BoltParms struct {
+	Thread      string  // name of thread (see ThreadLookup)
+	Style       string  // head style "hex", "socket", "button", "countersunk" or "knurl"
+	Tolerance   float64 // subtract from external thread radius
+	TotalLength float64 // threaded length + shank length
+	ShankLength float64 // non threaded length
+}
+
+// NewBolt3D returns a bolt.
+// The underside of the head is on the z = 0 plane and the bolt extends along +z.
+func NewBolt3D(k *BoltParms) (SDF3, error) {
+	t, err := ThreadLookupErr(k.Thread)
+	if err != nil {
+		return nil, err
+	}
+	if k.TotalLength <= 0 {
+		return nil, param_error("Bolt3D", "TotalLength", k.TotalLength, "must be > 0")
+	}
+	if k.ShankLength < 0 || k.ShankLength > k.TotalLength {
+		return nil, param_error("Bolt3D", "ShankLength", k.ShankLength, "must be >= 0 and <= TotalLength")
+	}
+	if k.Tolerance < 0 {
+		return nil, param_error("Bolt3D", "Tolerance", k.Tolerance, "must be >= 0")
+	}
+
+	// head
+	d := 2.0 * t.Radius
+	var head SDF3
+	var head_h float64
+	switch k.Style {
+	case "hex", "knurl":
+		head_r, err := t.Hex_RadiusErr()
+		if err != nil {
+			return nil, err
+		}
+		head_h, _ = t.Hex_HeightErr()
+		if k.Style == "hex" {
+			head = HexHead3D(head_r, head_h, "b")
+		} else {
+			head = KnurledHead3D(head_r, head_h, head_r*0.25)
+		}
+	case "socket":
+		head_h = d
+		head = Cylinder3D(head_h, 0.8*d, 0.05*d)
+		head = Difference3D(head, Transform3D(hex_socket(0.8*d, 0.5*d), Translate3d(V3{0, 0, -0.5 * head_h})))
+	case "button":
+		head_h = 0.55 * d
+		r := 0.875 * d
+		// spherical dome with a short cylindrical edge
+		h := 0.85 * head_h
+		sr := (r*r + h*h) / (2.0 * h)
+		dome := Transform3D(Sphere3D(sr), Translate3d(V3{0, 0, sr - 0.5*head_h}))
+		head = Intersect3D(Cylinder3D(head_h, r, 0), dome)
+		head = Difference3D(head, Transform3D(hex_socket(0.625*d, 0.35*d), Translate3d(V3{0, 0, -0.5 * head_h})))
+	case "countersunk":
+		head_h = 0.5 * d
+		head = Cone3D(head_h, d, 0.5*d, 0)
+		head = Difference3D(head, Transform3D(hex_socket(0.625*d, 0.35*d), Translate3d(V3{0, 0, -0.5 * head_h})))
+	default:
+		return nil, param_error("Bolt3D", "Style", k.Style, "unknown head style")
+	}
+	head = Transform3D(head, Translate3d(V3{0, 0, -0.5 * head_h}))
+	s := head
+
+	// shank
+	if k.ShankLength > 0 {
+		shank := Cylinder3D(k.ShankLength, t.Radius, 0)
+		shank = Transform3D(shank, Translate3d(V3{0, 0, 0.5 * k.ShankLength}))
+		s = Union3D(s, shank)
+	}
+
+	// thread
+	l := k.TotalLength - k.ShankLength
+	if l > 0 {
+		r := t.Radius - k.Tolerance
+		screw := TaperedScrew3D(thread_profile(t, r, "external"), l, t.Taper, t.Pitch, 1)
+		// chamfer the thread
+		screw = Chamfered_Cylinder(screw, 0, 0.5)
+		screw = Transform3D(screw, Translate3d(V3{0, 0, k.ShankLength + 0.5*l}))
+		s = Union3D(s, screw)
+	}
+
+	return s, nil
+}
+
+// Bolt3D returns a bolt (panic on error).
+func Bolt3D(k *BoltParms) SDF3 {
+	s, err := NewBolt3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+// Nuts
+
+type NutParms struct {
+	Thread    string  // name of thread (see ThreadLookup)
+	Style     string  // nut style "hex" or "knurl"
+	Tolerance float64 // add to internal thread radius
+	Height    float64 // height of nut (0 for the hex head height)
+}
+
+// NewNut3D returns a nut centered on the origin.
+func NewNut3D(k *NutParms) (SDF3, error) {
+	t, err := ThreadLookupErr(k.Thread)
+	if err != nil {
+		return nil, err
+	}
+	if k.Tolerance < 0 {
+		return nil, param_error("Nut3D", "Tolerance", k.Tolerance, "must be >= 0")
+	}
+	if k.Height < 0 {
+		return nil, param_error("Nut3D", "Height", k.Height, "must be >= 0")
+	}
+	nut_r, err := t.Hex_RadiusErr()
+	if err != nil {
+		return nil, err
+	}
+	nut_h := k.Height
+	if nut_h == 0 {
+		nut_h, _ = t.Hex_HeightErr()
+	}
+
+	var nut SDF3
+	switch k.Style {
+	case "hex":
+		nut = HexHead3D(nut_r, nut_h, "tb")
+	case "knurl":
+		nut = KnurledHead3D(nut_r, nut_h, nut_r*0.25)
+	default:
+		return nil, param_error("Nut3D", "Style", k.Style, "unknown nut style")
+	}
+
+	// internal thread
+	r := t.Radius + k.Tolerance
+	thread := TaperedScrew3D(thread_profile(t, r, "internal"), nut_h, t.Taper, t.Pitch, 1)
+	return Difference3D(nut, thread), nil
+}
+
+// Nut3D returns a nut centered on the origin (panic on error).
+func Nut3D(k *NutParms) SDF3 {
+	s, err := NewNut3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+// Washers
+
+type WasherParms struct {
+	Thread        string  // name of thread (see ThreadLookup)
+	Tolerance     float64 // add to the hole radius
+	Thickness     float64 // washer thickness (0 for 0.2d)
+	OuterDiameter float64 // outer diameter (0 for 2d)
+}
+
+// NewBoltWasher3D returns a plain washer for a thread, centered on the origin.
+// The default hole diameter (1.07d), outer diameter and thickness approximate ISO 7089.
+func NewBoltWasher3D(k *WasherParms) (SDF3, error) {
+	t, err := ThreadLookupErr(k.Thread)
+	if err != nil {
+		return nil, err
+	}
+	if k.Tolerance < 0 {
+		return nil, param_error("BoltWasher3D", "Tolerance", k.Tolerance, "must be >= 0")
+	}
+	d := 2.0 * t.Radius
+	thickness := k.Thickness
+	if thickness == 0 {
+		thickness = 0.2 * d
+	}
+	od := k.OuterDiameter
+	if od == 0 {
+		od = 2.0 * d
+	}
+	return NewWasher3D(thickness, 0.535*d+k.Tolerance, 0.5*od)
+}
+
+// BoltWasher3D returns a plain washer for a thread (panic on error).
+func BoltWasher3D(k *WasherParms) SDF3 {
+	s, err := NewBoltWasher3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
@@ -318,3 +318,260 @@ func MakeGenevaCam(
 }
 
 //-----------------------------------------------------------------------------
+// Cam Motion
+
+// The cam rotates counter-clockwise about the origin and the follower moves
+// along the +y axis. At a cam angle of 0 the follower is on the +y axis of
+// the cam profile. Lift is measured from the lowest follower position.
+
+// cam_follower_direction returns the follower direction in the cam frame.
+func cam_follower_direction(theta float64) V2 {
+	return V2{math.Sin(theta), math.Cos(theta)}
+}
+
+// cam_ray returns the outermost distance along the ray u where the cam
+// distance is offset (sphere tracing inwards from radius r).
+func cam_ray(cam SDF2, u V2, r, offset float64) (float64, error) {
+	for i := 0; i < 1000; i++ {
+		d := cam.Evaluate(u.MulScalar(r)) - offset
+		if d < 1e-9 {
+			return r, nil
+		}
+		r -= d
+		if r < 0 {
+			break
+		}
+	}
+	return 0, fmt.Errorf("follower does not contact the cam")
+}
+
+type CamMotion struct {
+	Angle        []float64 // cam angle (radians)
+	Position     []float64 // follower position (distance from the cam center)
+	Lift         []float64 // follower lift
+	Velocity     []float64 // follower velocity (per radian of cam rotation)
+	Acceleration []float64 // follower acceleration (per radian^2 of cam rotation)
+}
+
+// CamFollowerMotion returns the follower motion for a full revolution of a cam.
+// Follower types are "knife", "roller" (of a given radius) or "flat" (faced).
+// For a roller the position is the roller center, for a flat follower it is the face.
+// Multiply velocity by w and acceleration by w^2 for a cam speed of w radians/sec.
+func CamFollowerMotion(
+	cam SDF2, // cam profile
+	follower string, // follower type
+	radius float64, // roller radius
+	n int, // number of steps per revolution
+) (*CamMotion, error) {
+	if n < 8 {
+		return nil, param_error("CamFollowerMotion", "n", n, "must be >= 8")
+	}
+	// start outside the cam
+	bb := cam.BoundingBox()
+	r0 := Max(bb.Min.Length(), bb.Max.Length()) + radius + 1
+
+	m := CamMotion{}
+	m.Angle = make([]float64, n)
+	m.Position = make([]float64, n)
+	dtheta := TAU / float64(n)
+
+	switch follower {
+	case "knife", "roller":
+		if follower == "knife" {
+			radius = 0
+		} else if radius <= 0 {
+			return nil, param_error("CamFollowerMotion", "radius", radius, "must be > 0")
+		}
+		for i := range m.Angle {
+			m.Angle[i] = float64(i) * dtheta
+			r, err := cam_ray(cam, cam_follower_direction(m.Angle[i]), r0, radius)
+			if err != nil {
+				return nil, err
+			}
+			m.Position[i] = r
+		}
+	case "flat":
+		// sample the cam boundary, the face touches the extreme point
+		k := 16 * n
+		boundary := make(V2Set, k)
+		for i := range boundary {
+			u := cam_follower_direction(TAU * float64(i) / float64(k))
+			r, err := cam_ray(cam, u, r0, 0)
+			if err != nil {
+				return nil, err
+			}
+			boundary[i] = u.MulScalar(r)
+		}
+		for i := range m.Angle {
+			m.Angle[i] = float64(i) * dtheta
+			u := cam_follower_direction(m.Angle[i])
+			h := -math.MaxFloat64
+			for _, p := range boundary {
+				h = Max(h, p.Dot(u))
+			}
+			m.Position[i] = h
+		}
+	default:
+		return nil, param_error("CamFollowerMotion", "follower", follower, "must be knife, roller or flat")
+	}
+
+	// lift, velocity and acceleration
+	min := m.Position[0]
+	for _, x := range m.Position {
+		min = Min(min, x)
+	}
+	m.Lift = make([]float64, n)
+	m.Velocity = make([]float64, n)
+	m.Acceleration = make([]float64, n)
+	for i := range m.Position {
+		x0 := m.Position[(i+n-1)%n]
+		x1 := m.Position[i]
+		x2 := m.Position[(i+1)%n]
+		m.Lift[i] = x1 - min
+		m.Velocity[i] = (x2 - x0) / (2 * dtheta)
+		m.Acceleration[i] = (x2 - 2*x1 + x0) / (dtheta * dtheta)
+	}
+	return &m, nil
+}
+
+//-----------------------------------------------------------------------------
+// Cam Synthesis
+
+// motion laws for a rise, x and the result are normalised to [0,1]
+func cam_law(law string, x float64) float64 {
+	switch law {
+	case "cycloidal":
+		return x - math.Sin(TAU*x)/TAU
+	case "harmonic":
+		return 0.5 * (1 - math.Cos(PI*x))
+	case "polynomial":
+		// 3-4-5 polynomial
+		return x * x * x * (10 - 15*x + 6*x*x)
+	}
+	return x
+}
+
+type CamLiftParms struct {
+	Law       string  // motion law "cycloidal", "harmonic", "polynomial" (3-4-5) or "linear"
+	Lift      float64 // total follower lift
+	Rise      float64 // rise angle (radians)
+	HighDwell float64 // high dwell angle (radians)
+	Return    float64 // return angle (radians), the low dwell is the remainder
+}
+
+// MakeCamLift returns a rise-dwell-return-dwell lift curve.
+// The rise starts at a cam angle of 0.
+func MakeCamLift(k *CamLiftParms) (func(float64) float64, error) {
+	switch k.Law {
+	case "cycloidal", "harmonic", "polynomial", "linear":
+	default:
+		return nil, param_error("MakeCamLift", "Law", k.Law, "unknown motion law")
+	}
+	if k.Lift <= 0 {
+		return nil, param_error("MakeCamLift", "Lift", k.Lift, "must be > 0")
+	}
+	if k.Rise <= 0 {
+		return nil, param_error("MakeCamLift", "Rise", k.Rise, "must be > 0")
+	}
+	if k.Return <= 0 {
+		return nil, param_error("MakeCamLift", "Return", k.Return, "must be > 0")
+	}
+	if k.HighDwell < 0 || k.Rise+k.HighDwell+k.Return > TAU {
+		return nil, param_error("MakeCamLift", "HighDwell", k.HighDwell, "must be >= 0 and the total angle must be <= 2 pi")
+	}
+	law := k.Law
+	lift := k.Lift
+	a0 := k.Rise
+	a1 := a0 + k.HighDwell
+	a2 := a1 + k.Return
+	return func(theta float64) float64 {
+		theta = math.Mod(theta, TAU)
+		if theta < 0 {
+			theta += TAU
+		}
+		switch {
+		case theta < a0:
+			return lift * cam_law(law, theta/a0)
+		case theta < a1:
+			return lift
+		case theta < a2:
+			return lift * (1 - cam_law(law, (theta-a1)/(a2-a1)))
+		}
+		return 0
+	}, nil
+}
+
+type CamProfileParms struct {
+	BaseRadius     float64               // radius of the cam base circle
+	Lift           func(float64) float64 // follower lift versus cam angle (radians)
+	Follower       string                // follower type "knife", "roller" or "flat"
+	FollowerRadius float64               // roller radius
+	Facets         int                   // number of facets for the profile
+}
+
+// MakeCamProfile returns the cam profile that gives a follower lift curve.
+// See CamFollowerMotion for the conventions.
+func MakeCamProfile(k *CamProfileParms) (SDF2, error) {
+	if k.BaseRadius <= 0 {
+		return nil, param_error("MakeCamProfile", "BaseRadius", k.BaseRadius, "must be > 0")
+	}
+	if k.Lift == nil {
+		return nil, param_error("MakeCamProfile", "Lift", nil, "no lift function")
+	}
+	if k.Facets < 8 {
+		return nil, param_error("MakeCamProfile", "Facets", k.Facets, "must be >= 8")
+	}
+	// lift derivatives
+	const h = 1e-4
+	s := k.Lift
+	ds := func(theta float64) float64 { return (s(theta+h) - s(theta-h)) / (2 * h) }
+	dds := func(theta float64) float64 { return (s(theta+h) - 2*s(theta) + s(theta-h)) / (h * h) }
+
+	v := make(V2Set, k.Facets)
+	dtheta := TAU / float64(k.Facets)
+	switch k.Follower {
+	case "knife":
+		for i := range v {
+			theta := float64(i) * dtheta
+			v[i] = cam_follower_direction(theta).MulScalar(k.BaseRadius + s(theta))
+		}
+	case "roller":
+		rr := k.FollowerRadius
+		if rr <= 0 {
+			return nil, param_error("MakeCamProfile", "FollowerRadius", rr, "must be > 0")
+		}
+		for i := range v {
+			theta := float64(i) * dtheta
+			u := cam_follower_direction(theta)
+			du := V2{u.Y, -u.X}
+			// pitch curve (roller center)
+			rp := k.BaseRadius + rr + s(theta)
+			drp := ds(theta)
+			ddrp := dds(theta)
+			// the roller must be smaller than the convex radius of curvature
+			den := rp*rp + 2*drp*drp - rp*ddrp
+			if den > 0 && math.Pow(rp*rp+drp*drp, 1.5)/den < rr {
+				return nil, fmt.Errorf("roller radius is too large, the cam is undercut at %.1f degrees", RtoD(theta))
+			}
+			// the contact point is along the inward normal of the pitch curve
+			t := u.MulScalar(drp).Add(du.MulScalar(rp)).Normalize()
+			n := V2{-t.Y, t.X}
+			v[i] = u.MulScalar(rp).Sub(n.MulScalar(rr))
+		}
+	case "flat":
+		for i := range v {
+			theta := float64(i) * dtheta
+			if k.BaseRadius+s(theta)+dds(theta) <= 0 {
+				return nil, fmt.Errorf("base radius is too small, the cam has a cusp at %.1f degrees", RtoD(theta))
+			}
+			u := cam_follower_direction(theta)
+			du := V2{u.Y, -u.X}
+			v[i] = u.MulScalar(k.BaseRadius + s(theta)).Add(du.MulScalar(ds(theta)))
+		}
+	default:
+		return nil, param_error("MakeCamProfile", "Follower", k.Follower, "must be knife, roller or flat")
+	}
+	return NewPolygon2D(v)
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,248 @@
+//-----------------------------------------------------------------------------
+/*
+
+Space Deformations
+
+Bend, twist and taper an existing SDF3.
+
+The deformation is applied to the evaluation point, so the distance returned
+by the underlying SDF3 is no longer exact. Each deformation works out a bound
+on how much it can stretch space (a Lipschitz factor) and divides the distance
+by it. The resulting field under-estimates the true distance, which keeps
+the rendering correct at the cost of some extra evaluations.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+
+// axis_frame returns a rotation matrix that maps the axis onto +z.
+// For the z-axis the result is the identity. For other axes the local x-axis
+// is the world x-axis (or y-axis if the axis is close to x) with the axis
+// component removed.
+func axis_frame(axis V3) M44 {
+	a := axis.Normalize()
+	ref := V3{1, 0, 0}
+	if Abs(a.X) > 0.9 {
+		ref = V3{0, 1, 0}
+	}
+	u := ref.Sub(a.MulScalar(ref.Dot(a))).Normalize()
+	v := a.Cross(u)
+	return M44{
+		u.X, u.Y, u.Z, 0,
+		v.X, v.Y, v.Z, 0,
+		a.X, a.Y, a.Z, 0,
+		0, 0, 0, 1}
+}
+
+// radial_extent returns the maximum distance of the box from the z-axis.
+func radial_extent(bb Box3) float64 {
+	r := 0.0
+	for _, v := range bb.Vertices() {
+		r = Max(r, V2{v.X, v.Y}.Length())
+	}
+	return r
+}
+
+//-----------------------------------------------------------------------------
+// Twist
+
+type TwistSDF3 struct {
+	sdf     SDF3
+	rate    float64 // twist rate (radians per unit length)
+	k       float64 // distance correction factor
+	matrix  M44     // world to local
+	inverse M44     // local to world
+	bb      Box3
+}
+
+// Twist3D twists an SDF3 about an axis through the origin.
+// The rotation is rate radians per unit length along the axis.
+func Twist3D(sdf SDF3, axis V3, rate float64) SDF3 {
+	s := TwistSDF3{}
+	s.sdf = sdf
+	s.rate = rate
+	s.matrix = axis_frame(axis)
+	s.inverse = s.matrix.Inverse()
+	// work out the bounding box in the local frame
+	bb := s.matrix.MulBox(sdf.BoundingBox())
+	r := radial_extent(bb)
+	// a twist is a shear of (rate * r) in the tangential direction
+	t := Abs(rate) * r
+	s.k = 0.5 * (t + math.Sqrt(t*t+4))
+	bb = Box3{V3{-r, -r, bb.Min.Z}, V3{r, r, bb.Max.Z}}
+	s.bb = s.inverse.MulBox(bb)
+	return &s
+}
+
+// Evaluate returns the minimum distance to a twisted SDF3.
+func (s *TwistSDF3) Evaluate(p V3) float64 {
+	p = s.matrix.MulPosition(p)
+	xy := Rotate(-s.rate * p.Z).MulPosition(V2{p.X, p.Y})
+	q := s.inverse.MulPosition(V3{xy.X, xy.Y, p.Z})
+	return s.sdf.Evaluate(q) / s.k
+}
+
+// BoundingBox returns the bounding box for a twisted SDF3.
+func (s *TwistSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+// Bend
+
+type BendSDF3 struct {
+	sdf     SDF3
+	radius  float64 // bend radius (> 0)
+	sign    float64 // bend direction
+	k       float64 // distance correction factor
+	matrix  M44     // world to local
+	inverse M44     // local to world
+	bb      Box3
+}
+
+// arc_box returns the bounding box of a circular arc (center c, radius r)
+// swept from angle theta0 to theta1. Angles are measured from -y towards +x.
+func arc_box(c V2, r, theta0, theta1 float64) Box2 {
+	pt := func(theta float64) V2 {
+		return c.Add(V2{math.Sin(theta), -math.Cos(theta)}.MulScalar(r))
+	}
+	p0 := pt(theta0)
+	bb := Box2{p0, p0}
+	p1 := pt(theta1)
+	bb = bb.Extend(Box2{p1, p1})
+	// add any axis extremes within the arc
+	for i := -2; i <= 2; i++ {
+		theta := float64(i) * PI / 2
+		if theta > theta0 && theta < theta1 {
+			p := pt(theta)
+			bb = bb.Extend(Box2{p, p})
+		}
+	}
+	return bb
+}
+
+// Bend3D bends an SDF3 about an axis through the origin.
+// The local x-axis (see axis_frame) is wrapped around a circle of the given
+// radius centered on the local y-axis. For the z-axis the solid is bent from
+// the x-axis towards +y (or -y with a negative radius).
+// The bent portion of the solid must lie in y < radius and |x| < PI * radius.
+func Bend3D(sdf SDF3, axis V3, radius float64) SDF3 {
+	if radius == 0 {
+		panic("radius == 0")
+	}
+	s := BendSDF3{}
+	s.sdf = sdf
+	s.radius = Abs(radius)
+	s.sign = Sign(radius)
+	s.matrix = axis_frame(axis)
+	s.inverse = s.matrix.Inverse()
+	// work out the bounding box in the local frame
+	bb := s.matrix.MulBox(sdf.BoundingBox())
+	ymin, ymax := bb.Min.Y, bb.Max.Y
+	if s.sign < 0 {
+		ymin, ymax = -ymax, -ymin
+	}
+	if ymax >= s.radius {
+		panic("bend radius is too small for the object")
+	}
+	// arc length is stretched by radius/(radius - y) on the inside of the bend
+	s.k = 1
+	if ymax > 0 {
+		s.k = s.radius / (s.radius - ymax)
+	}
+	theta0 := Max(bb.Min.X/s.radius, -PI)
+	theta1 := Min(bb.Max.X/s.radius, PI)
+	c := V2{0, s.radius}
+	bb2 := arc_box(c, s.radius-ymax, theta0, theta1)
+	bb2 = bb2.Extend(arc_box(c, s.radius-ymin, theta0, theta1))
+	if s.sign < 0 {
+		bb2 = Box2{V2{bb2.Min.X, -bb2.Max.Y}, V2{bb2.Max.X, -bb2.Min.Y}}
+	}
+	bb = Box3{V3{bb2.Min.X, bb2.Min.Y, bb.Min.Z}, V3{bb2.Max.X, bb2.Max.Y, bb.Max.Z}}
+	s.bb = s.inverse.MulBox(bb)
+	return &s
+}
+
+// Evaluate returns the minimum distance to a bent SDF3.
+func (s *BendSDF3) Evaluate(p V3) float64 {
+	p = s.matrix.MulPosition(p)
+	y := s.sign * p.Y
+	// polar coordinates about the bend center
+	v := V2{p.X, s.radius - y}
+	theta := math.Atan2(v.X, v.Y)
+	// unbend the point
+	q := V3{s.radius * theta, s.sign * (s.radius - v.Length()), p.Z}
+	return s.sdf.Evaluate(s.inverse.MulPosition(q)) / s.k
+}
+
+// BoundingBox returns the bounding box for a bent SDF3.
+func (s *BendSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+// Taper
+
+type TaperSDF3 struct {
+	sdf     SDF3
+	z0, z1  float64 // axial extent of the taper
+	s0, s1  float64 // scale at z0 and z1
+	k       float64 // distance correction factor
+	matrix  M44     // world to local
+	inverse M44     // local to world
+	bb      Box3
+}
+
+// Taper3D scales an SDF3 perpendicular to an axis through the origin.
+// The scale changes linearly from scale0 to scale1 over the extent of the
+// object along the axis.
+func Taper3D(sdf SDF3, axis V3, scale0, scale1 float64) SDF3 {
+	if scale0 <= 0 || scale1 <= 0 {
+		panic("scale <= 0")
+	}
+	s := TaperSDF3{}
+	s.sdf = sdf
+	s.s0 = scale0
+	s.s1 = scale1
+	s.matrix = axis_frame(axis)
+	s.inverse = s.matrix.Inverse()
+	// work out the bounding box in the local frame
+	bb := s.matrix.MulBox(sdf.BoundingBox())
+	s.z0 = bb.Min.Z
+	s.z1 = bb.Max.Z
+	// work out the distance correction factor
+	smin := Min(scale0, scale1)
+	slope := 0.0
+	if s.z1 > s.z0 {
+		slope = Abs(scale1-scale0) / (s.z1 - s.z0)
+	}
+	s.k = Max(1, 1/smin) + radial_extent(bb)*slope/smin
+	// the extreme scaling is at one end or the other
+	bb0 := Box3{bb.Min.Mul(V3{scale0, scale0, 1}), bb.Max.Mul(V3{scale0, scale0, 1})}
+	bb1 := Box3{bb.Min.Mul(V3{scale1, scale1, 1}), bb.Max.Mul(V3{scale1, scale1, 1})}
+	s.bb = s.inverse.MulBox(bb0.Extend(bb1))
+	return &s
+}
+
+// Evaluate returns the minimum distance to a tapered SDF3.
+func (s *TaperSDF3) Evaluate(p V3) float64 {
+	p = s.matrix.MulPosition(p)
+	k := s.s0
+	if s.z1 > s.z0 {
+		k = Mix(s.s0, s.s1, Clamp((p.Z-s.z0)/(s.z1-s.z0), 0, 1))
+	}
+	q := V3{p.X / k, p.Y / k, p.Z}
+	return s.sdf.Evaluate(s.inverse.MulPosition(q)) / s.k
+}
+
+// BoundingBox returns the bounding box for a tapered SDF3.
+func (s *TaperSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
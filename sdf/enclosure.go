@@ -0,0 +1,244 @@
+//-----------------------------------------------------------------------------
+/*
+
+PCB Enclosures
+
+A two part enclosure (base and lid) generated from a board description:
+
+- the board outline (any SDF2, the enclosure fits its bounding box)
+- mounting hole positions (standoffs are placed under them)
+- edge connectors (cut-outs are made in the wall at the connector height)
+
+Coordinates are board coordinates in the xy plane. The inside floor of the
+base is on the z = 0 plane, the underside of the board is at BoardHeight and
+the underside of the lid is at Height. The parts are returned in their
+assembled positions, flip the lid over for printing.
+
+Connector presets (face size of the receptacle and center height above the
+top of the board):
+
+usb_c: USB type C receptacle (top mount)
+barrel_5.5x2.1: 5.5/2.1 mm DC barrel jack, round hole for the plug
+rj45: RJ45 modular jack
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+//-----------------------------------------------------------------------------
+// Connector Database - lookup edge connectors by name
+
+type ConnectorParameters struct {
+	Name   string  // name of connector
+	Size   V2      // cut-out width (along the board edge) and height
+	Round  float64 // corner radius of the cut-out
+	Height float64 // height of the cut-out center above the top of the board
+}
+
+type ConnectorDatabase map[string]*ConnectorParameters
+
+var connector_db = Init_ConnectorLookup()
+
+// ConnectorAdd adds a connector to the connector database (dimensions in mm).
+func (m ConnectorDatabase) ConnectorAdd(
+	name string, // connector name
+	w, h float64, // cut-out size
+	round float64, // corner radius
+	height float64, // height of the cut-out center above the board
+) {
+	k := ConnectorParameters{}
+	k.Name = name
+	k.Size = V2{w, h}
+	k.Round = round
+	k.Height = height
+	m[name] = &k
+}
+
+func Init_ConnectorLookup() ConnectorDatabase {
+	m := make(ConnectorDatabase)
+	m.ConnectorAdd("usb_c", 8.94, 3.26, 1.63, 1.63)
+	m.ConnectorAdd("barrel_5.5x2.1", 8.0, 8.0, 4.0, 6.5)
+	m.ConnectorAdd("rj45", 16.0, 13.5, 0.5, 6.75)
+	return m
+}
+
+// lookup the parameters for a connector by name
+func ConnectorLookupErr(name string) (*ConnectorParameters, error) {
+	k, ok := connector_db[name]
+	if !ok {
+		return nil, param_error("ConnectorLookup", "name", name, "connector name not found")
+	}
+	return k, nil
+}
+
+// lookup the parameters for a connector by name (panic on error)
+func ConnectorLookup(name string) *ConnectorParameters {
+	k, err := ConnectorLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+//-----------------------------------------------------------------------------
+// Enclosures
+
+type PCBConnector struct {
+	Connector string  // name of connector (see ConnectorLookup)
+	Edge      string  // board edge "+x", "-x", "+y" or "-y"
+	Position  float64 // position of the connector center along the edge
+}
+
+type EnclosureParms struct {
+	Board       SDF2           // board outline
+	Thickness   float64        // board thickness (0 for 1.6)
+	Holes       []V2           // mounting hole positions
+	Standoff    StandoffParms  // standoffs for the mounting holes (PillarHeight is set to BoardHeight)
+	Connectors  []PCBConnector // edge connectors
+	BoardHeight float64        // height of the underside of the board above the floor
+	Height      float64        // inside height from the floor to the lid
+	Wall        float64        // wall, floor and lid thickness
+	Clearance   float64        // gap between the board and the walls
+	Rounding    float64        // radius of the outside corners
+	Fit         float64        // clearance for the lid and the connector cut-outs (0 for 0.2)
+}
+
+// connector_cutout returns the wall cut-out for an edge connector.
+func connector_cutout(k *EnclosureParms, c *PCBConnector, cavity Box2, z float64) (SDF3, error) {
+	ck, err := ConnectorLookupErr(c.Connector)
+	if err != nil {
+		return nil, err
+	}
+	size := ck.Size.AddScalar(2.0 * k.Fit)
+	z += ck.Height
+	if z-0.5*size.Y < 0 || z+0.5*size.Y > k.Height {
+		return nil, param_error("Enclosure3D", "Connectors", c.Connector, "cut-out does not fit between the floor and the lid")
+	}
+	// cut from the board edge through the wall
+	depth := 2.0 * (k.Clearance + k.Wall)
+	s := Extrude3D(Box2D(size, ck.Round+k.Fit), depth)
+	var m M44
+	switch c.Edge {
+	case "+x":
+		m = Translate3d(V3{cavity.Max.X, c.Position, z}).Mul(RotateY(DtoR(90))).Mul(RotateZ(DtoR(90)))
+	case "-x":
+		m = Translate3d(V3{cavity.Min.X, c.Position, z}).Mul(RotateY(DtoR(90))).Mul(RotateZ(DtoR(90)))
+	case "+y":
+		m = Translate3d(V3{c.Position, cavity.Max.Y, z}).Mul(RotateX(DtoR(90)))
+	case "-y":
+		m = Translate3d(V3{c.Position, cavity.Min.Y, z}).Mul(RotateX(DtoR(90)))
+	default:
+		return nil, param_error("Enclosure3D", "Edge", c.Edge, "must be +x, -x, +y or -y")
+	}
+	return Transform3D(s, m), nil
+}
+
+// NewEnclosure3D returns the base and lid of an enclosure for a board.
+func NewEnclosure3D(k *EnclosureParms) ([]SDF3, error) {
+	if k.Board == nil {
+		return nil, param_error("Enclosure3D", "Board", nil, "board outline is required")
+	}
+	if k.Wall <= 0 {
+		return nil, param_error("Enclosure3D", "Wall", k.Wall, "must be > 0")
+	}
+	if k.Clearance < 0 {
+		return nil, param_error("Enclosure3D", "Clearance", k.Clearance, "must be >= 0")
+	}
+	if k.Rounding < 0 {
+		return nil, param_error("Enclosure3D", "Rounding", k.Rounding, "must be >= 0")
+	}
+	if k.Fit < 0 {
+		return nil, param_error("Enclosure3D", "Fit", k.Fit, "must be >= 0")
+	}
+	if k.Fit == 0 {
+		k.Fit = 0.2
+	}
+	if k.Thickness < 0 {
+		return nil, param_error("Enclosure3D", "Thickness", k.Thickness, "must be >= 0")
+	}
+	if k.Thickness == 0 {
+		k.Thickness = 1.6
+	}
+	if k.BoardHeight <= 0 {
+		return nil, param_error("Enclosure3D", "BoardHeight", k.BoardHeight, "must be > 0")
+	}
+	if k.Height <= k.BoardHeight+k.Thickness {
+		return nil, param_error("Enclosure3D", "Height", k.Height, "must be > BoardHeight + Thickness")
+	}
+
+	// base
+	cavity := k.Board.BoundingBox()
+	cavity = Box2{cavity.Min.SubScalar(k.Clearance), cavity.Max.AddScalar(k.Clearance)}
+	center := cavity.Center()
+	inner_size := cavity.Size()
+	outer_size := inner_size.AddScalar(2.0 * k.Wall)
+	inner_rounding := Max(0.0, k.Rounding-k.Wall)
+	outer := Box2D(outer_size, k.Rounding)
+	inner := Box2D(inner_size, inner_rounding)
+
+	h := k.Height + k.Wall
+	base := Transform3D(Extrude3D(outer, h), Translate3d(V3{0, 0, 0.5*h - k.Wall}))
+	space := Transform3D(Extrude3D(inner, h), Translate3d(V3{0, 0, 0.5 * h}))
+	base = Difference3D(base, space)
+
+	// lid with a lip inside the walls
+	lid := Transform3D(Extrude3D(outer, k.Wall), Translate3d(V3{0, 0, k.Height + 0.5*k.Wall}))
+	lip_size := inner_size.SubScalar(2.0 * k.Fit)
+	lip_rounding := Max(0.0, inner_rounding-k.Fit)
+	lip := Difference2D(Box2D(lip_size, lip_rounding), Box2D(lip_size.SubScalar(2.0*k.Wall), Max(0.0, lip_rounding-k.Wall)))
+	lip_h := 2.0 * k.Wall
+	lid = Union3D(lid, Transform3D(Extrude3D(lip, lip_h), Translate3d(V3{0, 0, k.Height - 0.5*lip_h})))
+
+	m := Translate3d(V3{center.X, center.Y, 0})
+	base = Transform3D(base, m)
+	lid = Transform3D(lid, m)
+
+	// standoffs
+	if len(k.Holes) > 0 {
+		sp := k.Standoff
+		if sp.PillarDiameter <= 0 {
+			return nil, param_error("Enclosure3D", "Standoff.PillarDiameter", sp.PillarDiameter, "must be > 0")
+		}
+		sp.PillarHeight = k.BoardHeight
+		if sp.HoleDepth == 0 {
+			sp.HoleDepth = sp.PillarHeight
+		}
+		positions := make(V3Set, len(k.Holes))
+		for i, p := range k.Holes {
+			if k.Board.Evaluate(p) >= 0 {
+				return nil, param_error("Enclosure3D", "Holes", p, "mounting hole is not on the board")
+			}
+			positions[i] = V3{p.X, p.Y, 0.5 * sp.PillarHeight}
+		}
+		base = Union3D(base, Standoffs3D(&sp, positions))
+	}
+
+	// connector cut-outs
+	var cutouts []SDF3
+	for i := range k.Connectors {
+		s, err := connector_cutout(k, &k.Connectors[i], cavity, k.BoardHeight+k.Thickness)
+		if err != nil {
+			return nil, err
+		}
+		cutouts = append(cutouts, s)
+	}
+	if len(cutouts) > 0 {
+		c := Union3D(cutouts...)
+		base = Difference3D(base, c)
+		lid = Difference3D(lid, c)
+	}
+
+	return []SDF3{base, lid}, nil
+}
+
+// Enclosure3D returns the base and lid of an enclosure for a board (panic on error).
+func Enclosure3D(k *EnclosureParms) []SDF3 {
+	s, err := NewEnclosure3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
@@ -8,7 +8,10 @@ Involute Gears
 
 package sdf
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 //-----------------------------------------------------------------------------
 
@@ -37,6 +40,7 @@ func involute_theta(
 //-----------------------------------------------------------------------------
 
 // InvoluteGearTooth returns a 2D profile for a single involute tooth.
+// See InvoluteGearToothShifted for a profile shifted tooth.
 func InvoluteGearTooth(
 	number_teeth int, // number of gear teeth
 	gear_module float64, // pitch circle diameter / number of gear teeth
@@ -46,14 +50,27 @@ func InvoluteGearTooth(
 	backlash float64, // backlash expressed as units of pitch circumference
 	facets int, // number of facets for involute flank
 ) SDF2 {
+	return involute_gear_tooth(float64(number_teeth), gear_module, root_radius, base_radius, outer_radius, backlash, 0, facets)
+}
 
-	pitch_radius := float64(number_teeth) * gear_module / 2.0
+// involute_gear_tooth returns a 2D profile for a single involute tooth.
+// The number of teeth need not be an integer (e.g. the virtual gear of a bevel gear).
+// A profile shift (x) thickens the tooth by 2 * x * module * tan(pressure angle).
+func involute_gear_tooth(
+	number_teeth float64,
+	gear_module, root_radius, base_radius, outer_radius, backlash, profile_shift float64,
+	facets int,
+) SDF2 {
+
+	pitch_radius := number_teeth * gear_module / 2.0
+	pressure_angle := math.Acos(base_radius / pitch_radius)
 
 	// work out the angular extent of the tooth on the base radius
 	pitch_point := involute_xy(base_radius, involute_theta(base_radius, pitch_radius))
 	face_angle := math.Atan2(pitch_point.Y, pitch_point.X)
 	backlash_angle := backlash / (2.0 * pitch_radius)
-	center_angle := PI/(2.0*float64(number_teeth)) + face_angle - backlash_angle
+	shift_angle := profile_shift * gear_module * math.Tan(pressure_angle) / pitch_radius
+	center_angle := PI/(2.0*number_teeth) + face_angle - backlash_angle + shift_angle
 
 	// work out the angles over which the involute will be used
 	start_angle := involute_theta(base_radius, Max(base_radius, root_radius))
@@ -85,6 +102,7 @@ func InvoluteGearTooth(
 //-----------------------------------------------------------------------------
 
 // InvoluteGear returns an 2D polygon for an involute gear.
+// See InvoluteGear2D for a gear with exact involute flanks.
 func InvoluteGear(
 	number_teeth int, // number of gear teeth
 	gear_module float64, // pitch circle diameter / number of gear teeth
@@ -94,6 +112,271 @@ func InvoluteGear(
 	ring_width float64, // width of ring wall (from root circle)
 	facets int, // number of facets for involute flank
 ) SDF2 {
+	return involute_gear(&InvoluteGearParms{
+		NumberTeeth:   number_teeth,
+		Module:        gear_module,
+		PressureAngle: pressure_angle,
+		Backlash:      backlash,
+		Clearance:     clearance,
+		RingWidth:     ring_width,
+		Facets:        facets,
+	})
+}
+
+// involute_gear returns an 2D polygon for an involute gear.
+func involute_gear(k *InvoluteGearParms) SDF2 {
+	_, root_radius := involute_gear_radii(k)
+	ring_radius := root_radius - k.RingWidth
+
+	gear := RotateCopy2D(InvoluteGearToothShifted(k), k.NumberTeeth)
+	root := Circle2D(root_radius)
+	ring := Circle2D(ring_radius)
+
+	return Difference2D(Union2D(gear, root), ring)
+}
+
+//-----------------------------------------------------------------------------
+// Profile Shifted Involute Gears
+
+type InvoluteGearParms struct {
+	NumberTeeth   int     // number of gear teeth
+	Module        float64 // pitch circle diameter / number of gear teeth
+	PressureAngle float64 // gear pressure angle (radians)
+	ProfileShift  float64 // profile shift coefficient (x), see GearPair
+	TipReduction  float64 // outside radius reduction, see GearPair
+	Backlash      float64 // backlash expressed as per-tooth distance at pitch circumference
+	Clearance     float64 // additional root clearance
+	RingWidth     float64 // width of ring wall (from root circle)
+	Facets        int     // number of facets for involute flank (0 for exact flanks, see InvoluteGear2D)
+}
+
+// involute_gear_radii returns the outer and root radii for a profile shifted involute gear.
+func involute_gear_radii(k *InvoluteGearParms) (float64, float64) {
+	pitch_radius := float64(k.NumberTeeth) * k.Module / 2.0
+	// addendum: radial distance from pitch circle to outside circle
+	addendum := k.Module*(1.0+k.ProfileShift) - k.TipReduction
+	// dedendum: radial distance from pitch circle to root circle
+	dedendum := k.Module*(1.0-k.ProfileShift) + k.Clearance
+	return pitch_radius + addendum, pitch_radius - dedendum
+}
+
+// InvoluteGearToothShifted returns a 2D polygon for a single profile shifted involute tooth.
+// The tooth flanks have k.Facets facets, RingWidth is not used.
+func InvoluteGearToothShifted(k *InvoluteGearParms) SDF2 {
+	pitch_radius := float64(k.NumberTeeth) * k.Module / 2.0
+	base_radius := pitch_radius * math.Cos(k.PressureAngle)
+	outer_radius, root_radius := involute_gear_radii(k)
+	return involute_gear_tooth(
+		float64(k.NumberTeeth),
+		k.Module,
+		root_radius,
+		base_radius,
+		outer_radius,
+		k.Backlash,
+		k.ProfileShift,
+		k.Facets,
+	)
+}
+
+// InvoluteGearShifted returns the 2D profile for a profile shifted involute gear.
+// With zero profile shift and tip reduction it is the same as InvoluteGear
+// (or InvoluteGear2D for Facets == 0).
+func InvoluteGearShifted(k *InvoluteGearParms) SDF2 {
+	if k.Facets == 0 {
+		return involute_gear_2d(k)
+	}
+	return involute_gear(k)
+}
+
+//-----------------------------------------------------------------------------
+// Analytic Involute Gears
+
+// InvoluteGearSDF2 is an involute gear with exact (not faceted) tooth flanks.
+type InvoluteGearSDF2 struct {
+	tooth_angle  float64 // angle subtended by a single gear tooth
+	base_radius  float64 // base radius for the involute
+	outer_radius float64 // radius for outside of gear
+	root_radius  float64 // radius for root of gear tooth
+	ring_radius  float64 // radius of inner gear ring
+	base_angle   float64 // polar angle of the involute at the base radius
+	tip_angle    float64 // half angle subtended by the top land of the tooth
+	root_angle   float64 // polar angle of the tooth flank at the root radius
+	start_t      float64 // involute unwrap parameter at the start of the flank
+	stop_t       float64 // involute unwrap parameter at the end of the flank
+	start_xy     V2      // involute start coordinate
+	stop_xy      V2      // involute stop coordinate
+	root_xy      V2      // tooth flank coordinate at the root radius
+	bb           Box2    // bounding box
+}
+
+// InvoluteGear2D returns the 2D profile for an involute gear.
+// The geometry is the same as InvoluteGear, but the involute flanks are exact.
+// A tooth is centered on the x-axis.
+func InvoluteGear2D(
+	number_teeth int, // number of gear teeth
+	gear_module float64, // pitch circle diameter / number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+	backlash float64, // backlash expressed as per-tooth distance at pitch circumference
+	clearance float64, // additional root clearance
+	ring_width float64, // width of ring wall (from root circle)
+) SDF2 {
+	return involute_gear_2d(&InvoluteGearParms{
+		NumberTeeth:   number_teeth,
+		Module:        gear_module,
+		PressureAngle: pressure_angle,
+		Backlash:      backlash,
+		Clearance:     clearance,
+		RingWidth:     ring_width,
+	})
+}
+
+// involute_gear_2d returns the 2D profile for an involute gear with exact flanks.
+func involute_gear_2d(k *InvoluteGearParms) SDF2 {
+	s := InvoluteGearSDF2{}
+
+	// tooth angle
+	s.tooth_angle = TAU / float64(k.NumberTeeth)
+
+	// radius at gear pitch line
+	pitch_radius := float64(k.NumberTeeth) * k.Module / 2.0
+	// radius for base circle of involute
+	s.base_radius = pitch_radius * math.Cos(k.PressureAngle)
+	// radius for outside of gear, radius for root of gear tooth
+	s.outer_radius, s.root_radius = involute_gear_radii(k)
+	// radius of inner gear ring
+	s.ring_radius = s.root_radius - k.RingWidth
+
+	// polar angle of the tooth flank at the base radius (see InvoluteGearTooth)
+	backlash_angle := k.Backlash / (2.0 * pitch_radius)
+	shift_angle := k.ProfileShift * k.Module * math.Tan(k.PressureAngle) / pitch_radius
+	s.base_angle = s.tooth_angle/4.0 + involute(k.PressureAngle) - backlash_angle + shift_angle
+
+	// the tooth is pointed if the flanks meet inside the outer radius
+	s.tip_angle = s.flank_angle(s.outer_radius)
+	if s.tip_angle < 0 {
+		s.outer_radius = s.base_radius / math.Cos(inverse_involute(s.base_angle))
+		s.tip_angle = 0
+	}
+	s.root_angle = s.flank_angle(s.root_radius)
+
+	// the involute portion of the tooth flank
+	start_radius := Max(s.base_radius, s.root_radius)
+	s.start_t = involute_theta(s.base_radius, start_radius)
+	s.stop_t = involute_theta(s.base_radius, s.outer_radius)
+	s.start_xy = PolarToXY(start_radius, s.flank_angle(start_radius))
+	s.stop_xy = PolarToXY(s.outer_radius, s.tip_angle)
+	s.root_xy = PolarToXY(s.root_radius, s.root_angle)
+
+	s.bb = Box2{V2{-s.outer_radius, -s.outer_radius}, V2{s.outer_radius, s.outer_radius}}
+	return &s
+}
+
+// flank_angle returns the polar angle of the upper tooth flank at radius r.
+// The flank is radial inside the base circle.
+func (s *InvoluteGearSDF2) flank_angle(r float64) float64 {
+	if r <= s.base_radius {
+		return s.base_angle
+	}
+	return s.base_angle - involute(math.Acos(s.base_radius/r))
+}
+
+// Evaluate returns the minimum distance to the involute gear.
+func (s *InvoluteGearSDF2) Evaluate(p V2) float64 {
+	// The gear is symmetric about each tooth center and tooth space center.
+	// Map p to the upper half of the 0th tooth (about the x-axis).
+	r := p.Length()
+	theta := Abs(SawTooth(math.Atan2(p.Y, p.X), s.tooth_angle))
+	q := PolarToXY(r, theta)
+
+	// top land
+	var d float64
+	if theta <= s.tip_angle {
+		d = Abs(r - s.outer_radius)
+	} else {
+		d = q.Sub(s.stop_xy).Length()
+	}
+
+	// root
+	if s.root_angle < 0.5*s.tooth_angle {
+		if theta >= s.root_angle {
+			d = Min(d, Abs(r-s.root_radius))
+		} else {
+			d = Min(d, q.Sub(s.root_xy).Length())
+		}
+	}
+
+	// involute flank
+	if r > s.base_radius {
+		// The normals of the involute are tangent to the base circle.
+		// Work out the involute point with a normal passing through p.
+		t := s.base_angle - theta + math.Acos(s.base_radius/r)
+		if t >= s.start_t && t <= s.stop_t {
+			d = Min(d, Abs(math.Sqrt(r*r-s.base_radius*s.base_radius)-s.base_radius*t))
+		}
+	}
+	d = Min(d, q.Sub(s.start_xy).Length())
+
+	// radial flank inside the base circle
+	if s.root_radius < s.base_radius {
+		n := V2{math.Cos(s.base_angle), math.Sin(s.base_angle)}
+		x := Clamp(q.Dot(n), s.root_radius, s.base_radius)
+		d = Min(d, q.Sub(n.MulScalar(x)).Length())
+	}
+
+	// inside or outside?
+	if r < s.root_radius || (r < s.outer_radius && theta < s.flank_angle(r)) {
+		d = -d
+	}
+
+	// inner ring
+	return Max(d, s.ring_radius-r)
+}
+
+// BoundingBox returns the bounding box for the involute gear.
+func (s *InvoluteGearSDF2) BoundingBox() Box2 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+// Internal (Ring) Gears
+
+// involute function: inv(a) = tan(a) - a
+func involute(a float64) float64 {
+	return math.Tan(a) - a
+}
+
+// InternalGearAddendum returns the tooth addendum for an internal gear that
+// avoids involute interference with a given pinion. The standard addendum
+// (1 module) is reduced (tip relief) so that the internal gear tip doesn't
+// reach beyond the end of the line of action at the pinion base circle.
+func InternalGearAddendum(
+	pinion_teeth int, // number of pinion teeth
+	ring_teeth int, // number of internal gear teeth
+	gear_module float64, // pitch circle diameter / number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+) float64 {
+	r1 := float64(pinion_teeth) * gear_module / 2.0
+	r2 := float64(ring_teeth) * gear_module / 2.0
+	rb2 := r2 * math.Cos(pressure_angle)
+	// distance from the internal gear center to the interference point
+	l := (r2 - r1) * math.Sin(pressure_angle)
+	ra2 := math.Sqrt(rb2*rb2 + l*l)
+	return Min(gear_module, r2-ra2)
+}
+
+// InternalInvoluteGear returns the 2D profile for an internal (ring) gear.
+// The internal teeth are centered between the tooth spaces, with a tooth
+// space centered on the x-axis.
+func InternalInvoluteGear(
+	number_teeth int, // number of gear teeth
+	gear_module float64, // pitch circle diameter / number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+	backlash float64, // backlash expressed as per-tooth distance at pitch circumference
+	clearance float64, // additional root clearance
+	addendum float64, // radial distance from pitch circle to tooth tip (see InternalGearAddendum)
+	rim_width float64, // width of outer rim (from root circle)
+	facets int, // number of facets for involute flank
+) SDF2 {
 
 	// pitch radius
 	pitch_radius := float64(number_teeth) * gear_module / 2.0
@@ -101,30 +384,82 @@ func InvoluteGear(
 	// base circle radius
 	base_radius := pitch_radius * math.Cos(pressure_angle)
 
-	// addendum: radial distance from pitch circle to outside circle
-	addendum := gear_module * 1.0
-	// dedendum: radial distance from pitch circle to root circle
-	dedendum := addendum + clearance
+	// The involute doesn't exist within the base circle, so the
+	// tooth tip can't extend inside it.
+	addendum = Min(addendum, pitch_radius-base_radius)
+	// dedendum: radial distance from pitch circle to root circle (outside)
+	// the mating pinion has an addendum of 1 module
+	dedendum := gear_module + clearance
 
-	outer_radius := pitch_radius + addendum
-	root_radius := pitch_radius - dedendum
-	ring_radius := root_radius - ring_width
+	tip_radius := pitch_radius - addendum
+	root_radius := pitch_radius + dedendum
+	rim_radius := root_radius + rim_width
 
-	tooth := InvoluteGearTooth(
+	// The tooth spaces of an internal gear have the shape of external gear teeth.
+	space := InvoluteGearTooth(
 		number_teeth,
 		gear_module,
-		root_radius,
+		tip_radius,
 		base_radius,
-		outer_radius,
-		backlash,
+		root_radius,
+		-backlash,
 		facets,
 	)
 
-	gear := RotateCopy2D(tooth, number_teeth)
-	root := Circle2D(root_radius)
-	ring := Circle2D(ring_radius)
+	spaces := Union2D(RotateCopy2D(space, number_teeth), Circle2D(tip_radius))
+	return Difference2D(Circle2D(rim_radius), spaces)
+}
 
-	return Difference2D(Union2D(gear, root), ring)
+// InternalGearCheck checks an internal gear and pinion for interference.
+// An error describing the problem is returned if the gears will not mesh.
+// The pinion is assumed to have standard (unshifted) teeth as generated by
+// InvoluteGear.
+func InternalGearCheck(
+	pinion_teeth int, // number of pinion teeth
+	ring_teeth int, // number of internal gear teeth
+	gear_module float64, // pitch circle diameter / number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+	addendum float64, // internal gear tooth addendum
+) error {
+	if pinion_teeth <= 0 || ring_teeth <= 0 {
+		return fmt.Errorf("invalid number of teeth, must be > 0")
+	}
+	if ring_teeth <= pinion_teeth {
+		return fmt.Errorf("the internal gear must have more teeth than the pinion")
+	}
+	z1 := float64(pinion_teeth)
+	z2 := float64(ring_teeth)
+	// pitch, base and tip radii
+	r1 := z1 * gear_module / 2.0
+	r2 := z2 * gear_module / 2.0
+	rb1 := r1 * math.Cos(pressure_angle)
+	rb2 := r2 * math.Cos(pressure_angle)
+	ra1 := r1 + gear_module
+	ra2 := r2 - Min(addendum, r2-rb2)
+	// center distance
+	a := r2 - r1
+	// pressure angles at the tips
+	aa1 := math.Acos(rb1 / ra1)
+	aa2 := math.Acos(rb2 / ra2)
+
+	// involute interference: the internal gear tip cuts into the pinion root
+	// (the tip circle must not extend inside the end of the line of action)
+	l := a * math.Sin(pressure_angle)
+	if ra2 < math.Sqrt(rb2*rb2+l*l)-TOLERANCE*r2 {
+		return fmt.Errorf("involute interference between %d tooth pinion and %d tooth internal gear", pinion_teeth, ring_teeth)
+	}
+
+	// trochoid interference: the pinion tip cuts into the internal gear tip as it leaves mesh
+	c1 := (ra2*ra2 - ra1*ra1 - a*a) / (2 * a * ra1)
+	c2 := (a*a + ra2*ra2 - ra1*ra1) / (2 * a * ra2)
+	if Abs(c1) <= 1 && Abs(c2) <= 1 {
+		theta1 := math.Acos(c1) + involute(aa1) - involute(pressure_angle)
+		theta2 := math.Acos(c2)
+		if theta1*z1/z2+involute(pressure_angle)-involute(aa2) < theta2 {
+			return fmt.Errorf("trochoid interference between %d tooth pinion and %d tooth internal gear", pinion_teeth, ring_teeth)
+		}
+	}
+	return nil
 }
 
 //-----------------------------------------------------------------------------
@@ -198,3 +533,524 @@ func (s *GearRackSDF2) BoundingBox() Box2 {
 }
 
 //-----------------------------------------------------------------------------
+// Helical and Herringbone Gears
+
+// The gear module and pressure angle for helical gears are given in the
+// normal plane (perpendicular to the teeth), so a helical gear meshes with
+// a helical rack cut by the same tool as a spur rack of the same module.
+// Mating helical gears on parallel shafts have opposite hands.
+
+// helical_transverse returns the transverse (plane of rotation) module
+// and pressure angle for a normal module and pressure angle.
+func helical_transverse(gear_module, pressure_angle, helix_angle float64) (float64, float64) {
+	c := math.Cos(helix_angle)
+	return gear_module / c, math.Atan(math.Tan(pressure_angle) / c)
+}
+
+// HelicalGearTwist returns the rotation of a helical gear profile over the face width.
+// The twist is positive (counter-clockwise looking down the z-axis) for a right hand gear.
+func HelicalGearTwist(
+	number_teeth int, // number of gear teeth
+	gear_module float64, // normal module
+	helix_angle float64, // helix angle at the pitch circle (radians)
+	face_width float64, // face width of the gear
+	left_hand bool, // left hand helix
+) float64 {
+	m, _ := helical_transverse(gear_module, 0, helix_angle)
+	pitch_radius := float64(number_teeth) * m / 2.0
+	twist := face_width * math.Tan(helix_angle) / pitch_radius
+	if left_hand {
+		return -twist
+	}
+	return twist
+}
+
+// helical_gear_profile returns the transverse profile for a helical gear.
+func helical_gear_profile(
+	number_teeth int,
+	gear_module, pressure_angle, helix_angle, backlash, clearance, ring_width float64,
+	facets int,
+) SDF2 {
+	if helix_angle < 0 || helix_angle >= DtoR(60) {
+		panic("invalid helix angle")
+	}
+	m, pa := helical_transverse(gear_module, pressure_angle, helix_angle)
+	return InvoluteGear(number_teeth, m, pa, backlash/math.Cos(helix_angle), clearance, ring_width, facets)
+}
+
+// HelicalGear3D returns a helical gear centered on the origin with its axis on the z-axis.
+func HelicalGear3D(
+	number_teeth int, // number of gear teeth
+	gear_module float64, // normal module
+	pressure_angle float64, // normal pressure angle (radians)
+	helix_angle float64, // helix angle at the pitch circle (radians)
+	backlash float64, // backlash expressed as per-tooth distance at pitch circumference
+	clearance float64, // additional root clearance
+	ring_width float64, // width of ring wall (from root circle)
+	face_width float64, // face width of the gear
+	left_hand bool, // left hand helix
+	facets int, // number of facets for involute flank
+) SDF3 {
+	gear := helical_gear_profile(number_teeth, gear_module, pressure_angle, helix_angle, backlash, clearance, ring_width, facets)
+	twist := HelicalGearTwist(number_teeth, gear_module, helix_angle, face_width, left_hand)
+	// TwistExtrude3D rotates the profile clockwise for a positive twist
+	return TwistExtrude3D(gear, face_width, -twist)
+}
+
+// HerringboneGear3D returns a herringbone (double helical) gear centered on the origin
+// with its axis on the z-axis. The hand is that of the upper (z > 0) half of the gear.
+func HerringboneGear3D(
+	number_teeth int, // number of gear teeth
+	gear_module float64, // normal module
+	pressure_angle float64, // normal pressure angle (radians)
+	helix_angle float64, // helix angle at the pitch circle (radians)
+	backlash float64, // backlash expressed as per-tooth distance at pitch circumference
+	clearance float64, // additional root clearance
+	ring_width float64, // width of ring wall (from root circle)
+	face_width float64, // total face width of the gear
+	left_hand bool, // left hand helix (upper half)
+	facets int, // number of facets for involute flank
+) SDF3 {
+	gear := helical_gear_profile(number_teeth, gear_module, pressure_angle, helix_angle, backlash, clearance, ring_width, facets)
+	twist := HelicalGearTwist(number_teeth, gear_module, helix_angle, face_width, left_hand)
+	k := twist / face_width
+	s := ExtrudeSDF3{}
+	s.sdf = gear
+	s.height = face_width / 2
+	// the two halves of the gear twist in opposite directions from z = 0
+	s.extrude = func(p V3) V2 {
+		return Rotate(-Abs(p.Z) * k).MulPosition(V2{p.X, p.Y})
+	}
+	l := gear.BoundingBox().Max.Length()
+	s.bb = Box3{V3{-l, -l, -s.height}, V3{l, l, s.height}}
+	return &s
+}
+
+//-----------------------------------------------------------------------------
+// Helical Rack
+
+// HelicalRack3D returns a helical gear rack.
+// The rack profile (see GearRack2D) is in the x/y plane and the teeth are
+// inclined at the helix angle across the face width (z-axis).
+// A rack placed below a helical gear of the same hand will mesh with it.
+func HelicalRack3D(
+	number_teeth float64, // number of rack teeth
+	gear_module float64, // normal module
+	pressure_angle float64, // normal pressure angle (radians)
+	helix_angle float64, // helix angle (radians)
+	backlash float64, // backlash expressed as units of pitch circumference
+	base_height float64, // height of rack base
+	face_width float64, // face width of the rack
+	left_hand bool, // left hand helix
+) SDF3 {
+	if helix_angle < 0 || helix_angle >= DtoR(60) {
+		panic("invalid helix angle")
+	}
+	m, pa := helical_transverse(gear_module, pressure_angle, helix_angle)
+	rack := GearRack2D(number_teeth, m, pa, backlash/math.Cos(helix_angle), base_height)
+	k := math.Tan(helix_angle)
+	if left_hand {
+		k = -k
+	}
+	s := ExtrudeSDF3{}
+	s.sdf = rack
+	s.height = face_width / 2
+	// shear the rack profile along the x-axis
+	s.extrude = func(p V3) V2 {
+		return V2{p.X - p.Z*k, p.Y}
+	}
+	bb := rack.BoundingBox()
+	dx := Abs(k) * s.height
+	s.bb = Box3{V3{bb.Min.X - dx, bb.Min.Y, -s.height}, V3{bb.Max.X + dx, bb.Max.Y, s.height}}
+	return &s
+}
+
+//-----------------------------------------------------------------------------
+// Bevel Gears
+
+// The teeth of a bevel gear are approximated with the Tredgold (back cone)
+// method. The involute profile of the virtual spur gear on the back cone is
+// mapped onto a sphere about the cone apex and scaled towards the apex,
+// giving an approximate spherical involute (octoid) tooth.
+
+type BevelGearParms struct {
+	NumberTeeth   int     // number of gear teeth
+	Module        float64 // pitch circle diameter / number of gear teeth (at the large end)
+	PressureAngle float64 // gear pressure angle (radians)
+	PitchAngle    float64 // pitch cone angle (radians), 0 to work it out from the mating gear
+	MatingTeeth   int     // number of teeth on the mating gear
+	ShaftAngle    float64 // angle between the shafts (radians), 0 for a right angle drive
+	FaceWidth     float64 // face width (along the cone)
+	Backlash      float64 // backlash expressed as per-tooth distance at pitch circumference
+	Clearance     float64 // additional root clearance
+	Facets        int     // number of facets for involute flank
+}
+
+type BevelGearSDF3 struct {
+	tooth      SDF2    // tooth profile of the virtual spur gear
+	pitch      float64 // angular tooth pitch
+	delta      float64 // pitch cone angle
+	theta_root float64 // root cone angle
+	cone       float64 // cone distance (apex to the large end pitch circle)
+	face_width float64 // face width
+	rv         float64 // pitch radius of the virtual spur gear
+	apex       float64 // z-height of the cone apex
+	bb         Box3    // bounding box
+}
+
+// BevelPitchAngle returns the pitch cone angle for a bevel gear meshing with
+// a mating gear at a given shaft angle.
+func BevelPitchAngle(
+	number_teeth int, // number of gear teeth
+	mating_teeth int, // number of teeth on the mating gear
+	shaft_angle float64, // angle between the shafts (radians)
+) float64 {
+	ratio := float64(mating_teeth) / float64(number_teeth)
+	return math.Atan2(math.Sin(shaft_angle), ratio+math.Cos(shaft_angle))
+}
+
+// BevelGear3D returns a bevel gear with its axis on the z-axis.
+// The large end pitch circle is in the z = 0 plane and the cone apex is on
+// the +z axis at the cone distance * cos(pitch angle). A tooth is centered
+// on the x-axis. Mating gears share the same cone apex.
+func BevelGear3D(k *BevelGearParms) SDF3 {
+	if k.NumberTeeth <= 0 {
+		panic("invalid number of teeth")
+	}
+	shaft_angle := k.ShaftAngle
+	if shaft_angle == 0 {
+		shaft_angle = PI / 2
+	}
+	delta := k.PitchAngle
+	if delta == 0 {
+		if k.MatingTeeth <= 0 {
+			panic("need a pitch angle or the mating gear teeth")
+		}
+		delta = BevelPitchAngle(k.NumberTeeth, k.MatingTeeth, shaft_angle)
+	}
+	if delta <= 0 || delta >= PI/2 {
+		panic("invalid pitch angle")
+	}
+	s := BevelGearSDF3{}
+	s.delta = delta
+	s.pitch = TAU / float64(k.NumberTeeth)
+	pitch_radius := float64(k.NumberTeeth) * k.Module / 2.0
+	s.cone = pitch_radius / math.Sin(delta)
+	if k.FaceWidth <= 0 || k.FaceWidth > s.cone/2 {
+		panic("invalid face width")
+	}
+	s.face_width = k.FaceWidth
+	s.apex = s.cone * math.Cos(delta)
+
+	// virtual spur gear on the back cone
+	nv := float64(k.NumberTeeth) / math.Cos(delta)
+	s.rv = pitch_radius / math.Cos(delta)
+	addendum := k.Module
+	dedendum := addendum + k.Clearance
+	s.theta_root = delta - dedendum/s.cone
+	if s.theta_root <= 0 {
+		panic("clearance is too large")
+	}
+	base_radius := s.rv * math.Cos(k.PressureAngle)
+	s.tooth = involute_gear_tooth(nv, k.Module, s.rv-dedendum, base_radius, s.rv+addendum, k.Backlash, 0, k.Facets)
+
+	// work out the bounding box
+	theta_tip := delta + addendum/s.cone
+	r := s.cone * math.Sin(theta_tip)
+	s.bb = Box3{V3{-r, -r, s.apex - s.cone}, V3{r, r, s.apex - (s.cone-s.face_width)*math.Cos(theta_tip)}}
+	return &s
+}
+
+// Evaluate returns the minimum distance to a bevel gear.
+func (s *BevelGearSDF3) Evaluate(p V3) float64 {
+	// spherical coordinates about the cone apex
+	w := V3{p.X, p.Y, s.apex - p.Z}
+	rho := w.Length()
+	// the gear lies between spheres about the apex
+	d := Max(rho-s.cone, s.cone-s.face_width-rho)
+	if rho < EPSILON {
+		return d
+	}
+	theta := math.Acos(Clamp(w.Z/rho, -1, 1))
+	phi := math.Atan2(w.Y, w.X)
+	// map onto the reference sphere and develop the back cone
+	phi -= s.pitch * math.Round(phi/s.pitch)
+	r := s.rv + s.cone*(theta-s.delta)
+	c := math.Cos(s.delta)
+	dt := math.MaxFloat64
+	for i := -1; i <= 1; i++ {
+		psi := (phi + float64(i)*s.pitch) * c
+		dt = Min(dt, s.tooth.Evaluate(V2{r * math.Cos(psi), r * math.Sin(psi)}))
+	}
+	// root cone
+	dt = Min(dt, s.cone*(theta-s.theta_root))
+	// scale towards the apex
+	return Max(d, dt*rho/s.cone)
+}
+
+// BoundingBox returns the bounding box for a bevel gear.
+func (s *BevelGearSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+// Worm Gears
+
+// The worm is a screw with a rack tooth profile in the axial plane. The worm
+// wheel is approximated as a helical gear (helix angle = worm lead angle) with
+// its tips cut by a throat (torus) about the worm axis.
+
+type WormGearParms struct {
+	Module        float64 // axial module of the worm (transverse module of the wheel)
+	PressureAngle float64 // axial pressure angle (radians)
+	Starts        int     // number of worm thread starts
+	WheelTeeth    int     // number of teeth on the worm wheel
+	LeadAngle     float64 // worm lead angle at the pitch radius (radians)
+	WormLength    float64 // length of the worm
+	FaceWidth     float64 // face width of the worm wheel
+	Backlash      float64 // backlash expressed as per-tooth distance at pitch circumference
+	Clearance     float64 // additional root clearance
+	LeftHand      bool    // left hand worm and wheel
+	Facets        int     // number of facets for involute flank
+}
+
+type WormGears struct {
+	CenterDistance float64 // worm axis to wheel axis distance
+	WormRadius     float64 // pitch radius of the worm
+	WheelRadius    float64 // pitch radius of the worm wheel
+	Ratio          float64 // reduction ratio (wheel teeth / starts)
+	Worm           SDF3    // worm with its axis parallel to the x-axis at y = center distance
+	Wheel          SDF3    // worm wheel with its axis on the z-axis
+}
+
+// WormThread returns the 2D thread profile for a worm (see Screw3D).
+func WormThread(
+	radius float64, // pitch radius of the worm
+	gear_module float64, // axial module
+	pressure_angle float64, // axial pressure angle (radians)
+	backlash float64, // backlash expressed as per-tooth distance at pitch circumference
+	clearance float64, // additional root clearance
+) SDF2 {
+	pitch := gear_module * PI
+	addendum := gear_module * 1.0
+	dedendum := addendum + clearance
+	r_tip := radius + addendum
+	r_root := radius - dedendum
+	if r_root <= 0 {
+		panic("worm radius is too small")
+	}
+	// half thread thickness at the pitch radius
+	t := 0.25*pitch - 0.5*backlash
+	x0 := t - addendum*math.Tan(pressure_angle)
+	x1 := t + dedendum*math.Tan(pressure_angle)
+	if x0 <= 0 || x1 >= 0.5*pitch {
+		panic("invalid pressure angle")
+	}
+	tp := NewPolygon()
+	tp.Add(pitch, 0)
+	tp.Add(pitch, r_root)
+	tp.Add(x1, r_root)
+	tp.Add(x0, r_tip)
+	tp.Add(-x0, r_tip)
+	tp.Add(-x1, r_root)
+	tp.Add(-pitch, r_root)
+	tp.Add(-pitch, 0)
+	return Polygon2D(tp.Vertices())
+}
+
+// involute_pointed_radius returns the radius at which an involute gear tooth becomes pointed.
+func involute_pointed_radius(
+	number_teeth int, // number of gear teeth
+	gear_module float64, // pitch circle diameter / number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+) float64 {
+	pitch_radius := float64(number_teeth) * gear_module / 2.0
+	base_radius := pitch_radius * math.Cos(pressure_angle)
+	// the tooth is pointed when inv(a) = PI/(2 * number_teeth) + inv(pressure_angle)
+	x := PI/(2.0*float64(number_teeth)) + involute(pressure_angle)
+	a0, a1 := pressure_angle, PI/2
+	for i := 0; i < 50; i++ {
+		a := 0.5 * (a0 + a1)
+		if involute(a) < x {
+			a0 = a
+		} else {
+			a1 = a
+		}
+	}
+	return base_radius / math.Cos(a0)
+}
+
+// MakeWormGears returns a worm and worm wheel positioned to mesh at the center distance.
+func MakeWormGears(k *WormGearParms) (*WormGears, error) {
+	if k.Module <= 0 {
+		return nil, fmt.Errorf("invalid module, must be > 0")
+	}
+	if k.Starts < 1 {
+		return nil, fmt.Errorf("invalid number of starts, must be >= 1")
+	}
+	if k.WheelTeeth < 1 {
+		return nil, fmt.Errorf("invalid number of wheel teeth, must be >= 1")
+	}
+	if k.LeadAngle <= 0 || k.LeadAngle >= DtoR(45) {
+		return nil, fmt.Errorf("invalid lead angle")
+	}
+	if k.PressureAngle <= 0 || k.PressureAngle >= DtoR(30) {
+		return nil, fmt.Errorf("invalid pressure angle")
+	}
+	if k.WormLength <= 0 || k.FaceWidth <= 0 {
+		return nil, fmt.Errorf("invalid dimensions, must be > 0")
+	}
+	if k.Facets <= 0 {
+		return nil, fmt.Errorf("invalid number of facets, must be > 0")
+	}
+
+	g := WormGears{}
+	pitch := k.Module * PI
+	// tan(lead angle) = lead / (2 * PI * worm pitch radius)
+	g.WormRadius = float64(k.Starts) * k.Module / (2.0 * math.Tan(k.LeadAngle))
+	g.WheelRadius = float64(k.WheelTeeth) * k.Module / 2.0
+	g.CenterDistance = g.WormRadius + g.WheelRadius
+	g.Ratio = float64(k.WheelTeeth) / float64(k.Starts)
+	if g.WormRadius-k.Module-k.Clearance <= 0 {
+		return nil, fmt.Errorf("lead angle is too large for the worm")
+	}
+	if k.FaceWidth > 2.0*(g.WormRadius+k.Module) {
+		return nil, fmt.Errorf("face width is too large for the worm")
+	}
+
+	// worm: rotate the screw onto the x-axis with a thread space at x = 0
+	starts := k.Starts
+	if k.LeftHand {
+		starts = -starts
+	}
+	thread := WormThread(g.WormRadius, k.Module, k.PressureAngle, k.Backlash/2, k.Clearance)
+	worm := Screw3D(thread, k.WormLength, pitch, starts)
+	// the thread crest facing the wheel (-y) is at z = -lead/4
+	shift := 0.5*pitch + 0.25*float64(starts)*pitch
+	m := Translate3d(V3{0, g.CenterDistance, 0}).Mul(RotateY(PI / 2)).Mul(Translate3d(V3{0, 0, shift}))
+	g.Worm = Transform3D(worm, m)
+
+	// wheel: a helical gear with a tooth facing the worm
+	// The teeth are extended and then cut back by the throat to follow the worm.
+	root_radius := g.WheelRadius - k.Module - k.Clearance
+	base_radius := g.WheelRadius * math.Cos(k.PressureAngle)
+	outer_radius := g.WheelRadius + k.Module
+	tip := Min(g.WheelRadius+1.5*k.Module, involute_pointed_radius(k.WheelTeeth, k.Module, k.PressureAngle)-0.25*k.Module)
+	outer_radius = Max(outer_radius, tip)
+	tooth := InvoluteGearTooth(k.WheelTeeth, k.Module, root_radius, base_radius, outer_radius, k.Backlash/2, k.Facets)
+	profile := Union2D(RotateCopy2D(tooth, k.WheelTeeth), Circle2D(root_radius))
+	twist := k.FaceWidth * math.Tan(k.LeadAngle) / g.WheelRadius
+	if k.LeftHand {
+		twist = -twist
+	}
+	// TwistExtrude3D rotates the profile clockwise for a positive twist
+	wheel := TwistExtrude3D(profile, k.FaceWidth, -twist)
+	wheel = Transform3D(wheel, RotateZ(PI/2))
+	// cut the throat (clear of the worm root)
+	throat := Circle2D(g.WormRadius - k.Module)
+	throat = Transform2D(throat, Translate2d(V2{g.CenterDistance, 0}))
+	g.Wheel = Difference3D(wheel, Revolve3D(throat))
+	return &g, nil
+}
+
+//-----------------------------------------------------------------------------
+// Gear Pairs
+
+// Profile shift moves the cutting rack out (x > 0) or in (x < 0) by x * module.
+// This thickens the tooth at the base and avoids undercut on small pinions.
+// A pair of shifted gears runs at a modified center distance and pressure
+// angle given by:
+//
+// inv(working_pressure_angle) = inv(pressure_angle) + 2 * tan(pressure_angle) * (x0 + x1) / (z0 + z1)
+// center_distance = standard_center_distance * cos(pressure_angle) / cos(working_pressure_angle)
+
+type GearPair struct {
+	Teeth                  [2]int     // number of gear teeth
+	Shift                  [2]float64 // profile shift coefficients
+	Module                 float64    // pitch circle diameter / number of gear teeth
+	PressureAngle          float64    // gear pressure angle (radians)
+	StandardCenterDistance float64    // center distance without profile shift
+	CenterDistance         float64    // operating center distance
+	WorkingPressureAngle   float64    // operating pressure angle (radians)
+	OuterRadius            [2]float64 // outside radius before the tip reduction
+	TipReduction           float64    // outside radius reduction to keep the standard root clearance (see InvoluteGearParms)
+	ContactRatio           float64    // transverse contact ratio (should be > 1.2)
+	MinShift               [2]float64 // minimum profile shift to avoid undercut
+	Undercut               [2]bool    // the gear teeth will be undercut
+}
+
+// GearUndercutShift returns the minimum profile shift coefficient that avoids
+// undercut for a gear with a standard (1 module) addendum.
+func GearUndercutShift(
+	number_teeth int, // number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+) float64 {
+	s := math.Sin(pressure_angle)
+	return 1.0 - float64(number_teeth)*s*s/2.0
+}
+
+// inverse_involute returns the angle a for which inv(a) = x.
+func inverse_involute(x float64) float64 {
+	a0, a1 := 0.0, PI/2-EPSILON
+	for i := 0; i < 60; i++ {
+		a := 0.5 * (a0 + a1)
+		if involute(a) < x {
+			a0 = a
+		} else {
+			a1 = a
+		}
+	}
+	return 0.5 * (a0 + a1)
+}
+
+// NewGearPair works out the operating parameters for a pair of external spur gears.
+func NewGearPair(
+	teeth0, teeth1 int, // number of gear teeth
+	gear_module float64, // pitch circle diameter / number of gear teeth
+	pressure_angle float64, // gear pressure angle (radians)
+	shift0, shift1 float64, // profile shift coefficients
+) (*GearPair, error) {
+	if teeth0 < 1 || teeth1 < 1 {
+		return nil, fmt.Errorf("invalid number of teeth, must be >= 1")
+	}
+	if gear_module <= 0 {
+		return nil, fmt.Errorf("invalid module, must be > 0")
+	}
+	if pressure_angle <= 0 || pressure_angle >= DtoR(45) {
+		return nil, fmt.Errorf("invalid pressure angle")
+	}
+	g := GearPair{}
+	g.Teeth = [2]int{teeth0, teeth1}
+	g.Shift = [2]float64{shift0, shift1}
+	g.Module = gear_module
+	g.PressureAngle = pressure_angle
+
+	z := float64(teeth0 + teeth1)
+	inv := involute(pressure_angle) + 2.0*math.Tan(pressure_angle)*(shift0+shift1)/z
+	if inv <= 0 {
+		return nil, fmt.Errorf("total profile shift is too negative")
+	}
+	g.WorkingPressureAngle = inverse_involute(inv)
+	g.StandardCenterDistance = z * gear_module / 2.0
+	g.CenterDistance = g.StandardCenterDistance * math.Cos(pressure_angle) / math.Cos(g.WorkingPressureAngle)
+	// center distance modification: y * module
+	y := (g.CenterDistance - g.StandardCenterDistance) / gear_module
+	g.TipReduction = (shift0 + shift1 - y) * gear_module
+
+	// contact ratio: length of the path of contact / base pitch
+	l := -g.CenterDistance * math.Sin(g.WorkingPressureAngle)
+	for i := 0; i < 2; i++ {
+		r := float64(g.Teeth[i]) * gear_module / 2.0
+		rb := r * math.Cos(pressure_angle)
+		g.OuterRadius[i] = r + gear_module*(1.0+g.Shift[i])
+		// the gears are made with the reduced outside radius
+		ra := g.OuterRadius[i] - g.TipReduction
+		l += math.Sqrt(ra*ra - rb*rb)
+		g.MinShift[i] = GearUndercutShift(g.Teeth[i], pressure_angle)
+		g.Undercut[i] = g.Shift[i] < g.MinShift[i]
+	}
+	g.ContactRatio = l / (PI * gear_module * math.Cos(pressure_angle))
+	return &g, nil
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,193 @@
+//-----------------------------------------------------------------------------
+/*
+
+Helices and Springs
+
+A helix is made by sweeping a 2D wire profile along a helical path about the
+z-axis. The wire profile is defined in the plane normal to the helix with the
+x-axis pointing radially outwards and the y-axis pointing (roughly) along the
+z-axis. The wire profile should be centered on the origin.
+
+Unlike Screw3D the wire need not touch the z-axis, so this can be used for
+coil springs and helical wires of arbitrary profile.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+
+type HelixSDF3 struct {
+	wire   SDF2    // wire profile
+	radius float64 // radius of the helix (to the center of the wire)
+	pitch  float64 // rise per turn
+	turns  float64 // total number of turns
+	closed bool    // closed ends: the end turns are flat
+	ground bool    // ground ends: the ends are cut flat
+	left   bool    // left hand helix
+	height float64 // z-distance between the end points of the helix center line
+	bb     Box3    // bounding box
+}
+
+// Helix3D returns a helical wire about the z-axis with open ends.
+// The helix is centered on the origin. Use turns < 0 for a left hand helix.
+func Helix3D(
+	radius float64, // radius of the helix (to the center of the wire)
+	pitch float64, // rise per turn
+	turns float64, // number of turns (< 0 for a left hand helix)
+	wire SDF2, // wire profile
+) SDF3 {
+	return Spring3D(&SpringParms{
+		Radius:   radius,
+		Pitch:    pitch,
+		Turns:    Abs(turns),
+		Wire:     wire,
+		Ends:     "open",
+		LeftHand: turns < 0,
+	})
+}
+
+type SpringParms struct {
+	Radius   float64 // radius of the coil (to the center of the wire)
+	Pitch    float64 // rise per active turn
+	Turns    float64 // total number of turns (including end turns)
+	Wire     SDF2    // wire profile
+	Ends     string  // "open", "closed" (flat end turns) or "ground" (closed and ground flat)
+	LeftHand bool    // left hand coil
+}
+
+// Spring3D returns a coil spring about the z-axis.
+// The spring is centered on the origin.
+func Spring3D(k *SpringParms) SDF3 {
+	if k.Radius <= 0 {
+		panic("invalid radius")
+	}
+	if k.Pitch <= 0 {
+		panic("invalid pitch")
+	}
+	if k.Wire == nil {
+		panic("no wire profile")
+	}
+	s := HelixSDF3{}
+	s.wire = k.Wire
+	s.radius = k.Radius
+	s.pitch = k.Pitch
+	s.turns = k.Turns
+	s.left = k.LeftHand
+	switch k.Ends {
+	case "", "open":
+	case "closed":
+		s.closed = true
+	case "ground":
+		s.closed = true
+		s.ground = true
+	default:
+		panic("invalid spring ends")
+	}
+	if s.closed {
+		if s.turns <= 2 {
+			panic("closed springs need more than 2 turns")
+		}
+		s.height = s.pitch * (s.turns - 2)
+	} else {
+		if s.turns <= 0 {
+			panic("invalid number of turns")
+		}
+		s.height = s.pitch * s.turns
+	}
+	// work out the bounding box
+	wbb := s.wire.BoundingBox()
+	r := s.radius + Max(wbb.Max.X, -wbb.Min.X)
+	// the wire profile is normal to the inclined helix, so it is stretched along z
+	dz := s.pitch / TAU
+	cos := s.radius / math.Sqrt(s.radius*s.radius+dz*dz)
+	h := 0.5*s.height + Max(wbb.Max.Y, -wbb.Min.Y)/cos
+	if s.ground {
+		h = 0.5 * s.height
+	}
+	s.bb = Box3{V3{-r, -r, -h}, V3{r, r, h}}
+	return &s
+}
+
+// center_z returns the z-height and slope (dz/dphi) of the helix center line.
+func (s *HelixSDF3) center_z(phi float64) (float64, float64) {
+	u := phi / TAU
+	k := s.pitch / TAU
+	if s.closed {
+		// the first and last turns are flat
+		if u < 1 {
+			return -0.5 * s.height, 0
+		}
+		if u > s.turns-1 {
+			return 0.5 * s.height, 0
+		}
+		u -= 1
+	}
+	return s.pitch*u - 0.5*s.height, k
+}
+
+// distance returns the distance to the helix for an unwrapped angle phi.
+func (s *HelixSDF3) distance(p V3, phi float64) float64 {
+	phi_max := TAU * s.turns
+	if phi >= 0 && phi <= phi_max {
+		// work in the plane through the z-axis
+		z, dz := s.center_z(phi)
+		// adjust for the inclination of the wire
+		cos := s.radius / math.Sqrt(s.radius*s.radius+dz*dz)
+		x := V2{p.X, p.Y}.Length() - s.radius
+		return s.wire.Evaluate(V2{x, (p.Z - z) * cos})
+	}
+	// beyond the end of the wire, work in the frame at the end point
+	if phi < -PI || phi > phi_max+PI {
+		// on the other side of the z-axis, another turn will be closer
+		return math.MaxFloat64
+	}
+	phi = Clamp(phi, 0, phi_max)
+	z, dz := s.center_z(phi)
+	n := V3{math.Cos(phi), math.Sin(phi), 0}
+	t := V3{-s.radius * n.Y, s.radius * n.X, dz}.Normalize()
+	w := p.Sub(V3{s.radius * n.X, s.radius * n.Y, z})
+	d := s.wire.Evaluate(V2{w.Dot(n), w.Dot(n.Cross(t))})
+	l := w.Dot(t)
+	if phi == 0 {
+		l = -l
+	}
+	return Max(d, l)
+}
+
+// Evaluate returns the minimum distance to the helix.
+func (s *HelixSDF3) Evaluate(p V3) float64 {
+	if s.left {
+		p.Y = -p.Y
+	}
+	theta := math.Atan2(p.Y, p.X)
+	if theta < 0 {
+		theta += TAU
+	}
+	// estimate the turn closest to p
+	u := (p.Z + 0.5*s.height) / s.pitch
+	if s.closed {
+		u += 1
+	}
+	k0 := int(math.Floor(u - theta/TAU))
+	k1 := int(math.Ceil(s.turns))
+	// check the neighbouring turns and the end turns
+	d := math.MaxFloat64
+	for _, k := range []int{k0 - 1, k0, k0 + 1, -1, 0, 1, k1 - 1, k1} {
+		d = Min(d, s.distance(p, theta+TAU*float64(k)))
+	}
+	if s.ground {
+		d = Max(d, Abs(p.Z)-0.5*s.height)
+	}
+	return d
+}
+
+// BoundingBox returns the bounding box for the helix.
+func (s *HelixSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
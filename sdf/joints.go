@@ -0,0 +1,299 @@
+//-----------------------------------------------------------------------------
+/*
+
+Snap-Fits and Hinges
+
+Cantilever snap-fits:
+
+A cantilever beam of length l, thickness h and width b with a hook of depth y
+at the end. For a beam of constant rectangular section:
+
+strain = 1.5 * h * y / l^2
+deflection force P = (b * h^2 / 6) * E * strain / l
+mating force W = P * (mu + tan(a)) / (1 - mu * tan(a))
+
+where E is the modulus of the material, mu is the coefficient of friction and
+a is the hook entry angle (measured from the insertion direction). The
+separation force uses the retention angle in the same way, a retention
+angle of 90 degrees gives a permanent joint.
+
+With dimensions in mm and the modulus in MPa forces are in N.
+
+Printed parts are much weaker across the layers, so print the beam with the
+layers along its length.
+
+Print-in-place hinges:
+
+Alternate knuckles belong to each leaf. The pin is part of one leaf and passes
+through the knuckles of the other leaf with a radial clearance. Print with
+the hinge axis horizontal.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import (
+	"fmt"
+	"math"
+)
+
+//-----------------------------------------------------------------------------
+// Snap-Fit Materials
+
+type SnapMaterial struct {
+	Name     string  // name of the material
+	Modulus  float64 // flexural modulus (MPa)
+	Strain   float64 // permissible strain for a single snap (fraction)
+	Friction float64 // coefficient of friction (against itself)
+}
+
+type SnapMaterialDatabase map[string]*SnapMaterial
+
+var snap_material_db = Init_SnapMaterialLookup()
+
+// SnapMaterialAdd adds a material to the snap-fit material database.
+func (m SnapMaterialDatabase) SnapMaterialAdd(
+	name string, // material name
+	modulus float64, // flexural modulus (MPa)
+	strain float64, // permissible strain (fraction)
+	friction float64, // coefficient of friction
+) {
+	k := SnapMaterial{}
+	k.Name = name
+	k.Modulus = modulus
+	k.Strain = strain
+	k.Friction = friction
+	m[name] = &k
+}
+
+// Init_SnapMaterialLookup returns the materials database.
+// These are conservative values for FDM printed parts.
+func Init_SnapMaterialLookup() SnapMaterialDatabase {
+	m := make(SnapMaterialDatabase)
+	m.SnapMaterialAdd("PLA", 3500, 0.02, 0.4)
+	m.SnapMaterialAdd("PETG", 2100, 0.035, 0.4)
+	m.SnapMaterialAdd("ABS", 2200, 0.03, 0.5)
+	m.SnapMaterialAdd("ASA", 2000, 0.03, 0.5)
+	m.SnapMaterialAdd("PA12", 1500, 0.04, 0.3)
+	return m
+}
+
+// lookup the parameters for a snap-fit material by name
+func SnapMaterialLookupErr(name string) (*SnapMaterial, error) {
+	k, ok := snap_material_db[name]
+	if !ok {
+		return nil, param_error("SnapMaterialLookup", "name", name, "material name not found")
+	}
+	return k, nil
+}
+
+// lookup the parameters for a snap-fit material by name (panic on error)
+func SnapMaterialLookup(name string) *SnapMaterial {
+	k, err := SnapMaterialLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+//-----------------------------------------------------------------------------
+// Cantilever Snap-Fits
+
+// SnapFitStrain returns the strain at the root of a cantilever beam.
+func SnapFitStrain(
+	length float64, // beam length
+	thickness float64, // beam thickness
+	deflection float64, // deflection at the end of the beam
+) float64 {
+	return 1.5 * thickness * deflection / (length * length)
+}
+
+// SnapFitLength returns the minimum beam length for a given strain.
+func SnapFitLength(
+	thickness float64, // beam thickness
+	deflection float64, // deflection at the end of the beam
+	strain float64, // permissible strain
+) float64 {
+	return math.Sqrt(1.5 * thickness * deflection / strain)
+}
+
+// snap_force returns the axial force to push a hook face of angle a past the beam.
+func snap_force(p, mu, a float64) float64 {
+	tan := math.Tan(a)
+	if mu*tan >= 1 {
+		// self locking
+		return math.Inf(1)
+	}
+	return p * (mu + tan) / (1 - mu*tan)
+}
+
+type SnapFitParms struct {
+	Length         float64 // beam length from the root to the hook
+	Thickness      float64 // beam thickness
+	Width          float64 // beam width
+	Undercut       float64 // hook depth (the deflection needed to engage)
+	EntryAngle     float64 // hook entry angle (radians, 0 for 30 degrees)
+	RetentionAngle float64 // hook retention angle (radians, 0 for 90 degrees)
+	Material       string  // beam material (see SnapMaterialLookup)
+}
+
+type SnapFit struct {
+	Strain          float64 // strain at the root for full deflection
+	Force           float64 // deflection force at the hook
+	MatingForce     float64 // insertion force
+	SeparationForce float64 // removal force (+Inf for a permanent joint)
+	MinLength       float64 // minimum beam length for the material
+	Hook            SDF3    // snap-fit hook
+}
+
+// MakeSnapFit designs a cantilever snap-fit hook.
+// The root of the beam is on the z = 0 plane and the beam extends along +z.
+// The beam is on the +x side of the yz plane with the hook on its +x side.
+// The width of the beam is centered on the xz plane.
+func MakeSnapFit(k *SnapFitParms) (*SnapFit, error) {
+	if k.Length <= 0 {
+		return nil, param_error("SnapFit", "Length", k.Length, "must be > 0")
+	}
+	if k.Thickness <= 0 {
+		return nil, param_error("SnapFit", "Thickness", k.Thickness, "must be > 0")
+	}
+	if k.Width <= 0 {
+		return nil, param_error("SnapFit", "Width", k.Width, "must be > 0")
+	}
+	if k.Undercut <= 0 {
+		return nil, param_error("SnapFit", "Undercut", k.Undercut, "must be > 0")
+	}
+	entry := k.EntryAngle
+	if entry == 0 {
+		entry = DtoR(30)
+	}
+	if entry < 0 || entry >= DtoR(90) {
+		return nil, param_error("SnapFit", "EntryAngle", k.EntryAngle, "must be > 0 and < 90 degrees")
+	}
+	retention := k.RetentionAngle
+	if retention == 0 {
+		retention = DtoR(90)
+	}
+	if retention < 0 || retention > DtoR(90) {
+		return nil, param_error("SnapFit", "RetentionAngle", k.RetentionAngle, "must be > 0 and <= 90 degrees")
+	}
+	m, err := SnapMaterialLookupErr(k.Material)
+	if err != nil {
+		return nil, err
+	}
+
+	s := SnapFit{}
+	s.Strain = SnapFitStrain(k.Length, k.Thickness, k.Undercut)
+	s.MinLength = SnapFitLength(k.Thickness, k.Undercut, m.Strain)
+	if s.Strain > m.Strain {
+		msg := fmt.Sprintf("strain %.4f exceeds %.4f for %s, must be >= %.2f", s.Strain, m.Strain, m.Name, s.MinLength)
+		return nil, param_error("SnapFit", "Length", k.Length, msg)
+	}
+	s.Force = (k.Width * k.Thickness * k.Thickness / 6.0) * m.Modulus * s.Strain / k.Length
+	s.MatingForce = snap_force(s.Force, m.Friction, entry)
+	if retention == DtoR(90) {
+		s.SeparationForce = math.Inf(1)
+	} else {
+		s.SeparationForce = snap_force(s.Force, m.Friction, retention)
+	}
+
+	// hook profile in the xz plane
+	h := k.Thickness
+	y := k.Undercut
+	l := k.Length
+	p := NewPolygon()
+	p.Add(0, 0)
+	p.Add(h, 0)
+	p.Add(h, l-y/math.Tan(retention))
+	p.Add(h+y, l)
+	p.Add(h, l+y/math.Tan(entry))
+	p.Add(0, l+y/math.Tan(entry))
+	hook := Extrude3D(Polygon2D(p.Vertices()), k.Width)
+	s.Hook = Transform3D(hook, RotateX(DtoR(90)))
+	return &s, nil
+}
+
+//-----------------------------------------------------------------------------
+// Print-in-place Hinges
+
+type HingeParms struct {
+	Length        float64 // total hinge length along the axis
+	Diameter      float64 // knuckle diameter
+	PinDiameter   float64 // pin diameter
+	Knuckles      int     // number of knuckles (>= 2)
+	Clearance     float64 // radial and axial clearance between the moving parts
+	LeafWidth     float64 // leaf width from the hinge axis
+	LeafThickness float64 // leaf thickness (<= Diameter)
+}
+
+// NewHinge3D returns the two leaves of a print-in-place hinge.
+// The hinge axis is the x-axis, the hinge is centered on the origin and the
+// leaves extend along -y and +y. The first leaf has the pin.
+func NewHinge3D(k *HingeParms) ([]SDF3, error) {
+	if k.Knuckles < 2 {
+		return nil, param_error("Hinge3D", "Knuckles", k.Knuckles, "must be >= 2")
+	}
+	if k.Clearance <= 0 {
+		return nil, param_error("Hinge3D", "Clearance", k.Clearance, "must be > 0")
+	}
+	if k.Diameter <= 0 {
+		return nil, param_error("Hinge3D", "Diameter", k.Diameter, "must be > 0")
+	}
+	if k.PinDiameter <= 0 || k.PinDiameter+2.0*k.Clearance >= k.Diameter {
+		return nil, param_error("Hinge3D", "PinDiameter", k.PinDiameter, "must be > 0 and < Diameter - 2 * Clearance")
+	}
+	if k.LeafThickness <= 0 || k.LeafThickness > k.Diameter {
+		return nil, param_error("Hinge3D", "LeafThickness", k.LeafThickness, "must be > 0 and <= Diameter")
+	}
+	r := 0.5 * k.Diameter
+	if k.LeafWidth <= r+k.Clearance {
+		return nil, param_error("Hinge3D", "LeafWidth", k.LeafWidth, "must be > Diameter/2 + Clearance")
+	}
+	n := float64(k.Knuckles)
+	kl := (k.Length - (n-1)*k.Clearance) / n
+	if kl <= 0 {
+		return nil, param_error("Hinge3D", "Length", k.Length, "too short for the knuckles and clearance")
+	}
+
+	// the knuckles and the clearance space around them
+	var knuckles [2][]SDF3
+	var spaces [2][]SDF3
+	m := RotateY(DtoR(90))
+	for i := 0; i < k.Knuckles; i++ {
+		x := -0.5*k.Length + (0.5+float64(i))*kl + float64(i)*k.Clearance
+		knuckle := Transform3D(Cylinder3D(kl, r, 0), Translate3d(V3{x, 0, 0}).Mul(m))
+		space := Transform3D(Cylinder3D(kl+2.0*k.Clearance, r+k.Clearance, 0), Translate3d(V3{x, 0, 0}).Mul(m))
+		knuckles[i%2] = append(knuckles[i%2], knuckle)
+		spaces[i%2] = append(spaces[i%2], space)
+	}
+
+	// pin and pin hole
+	pin := Transform3D(Cylinder3D(k.Length, 0.5*k.PinDiameter, 0), m)
+	hole := Transform3D(Cylinder3D(k.Length, 0.5*k.PinDiameter+k.Clearance, 0), m)
+
+	// leaves
+	w := k.LeafWidth
+	plate := Box3D(V3{k.Length, w, k.LeafThickness}, 0)
+	plate0 := Transform3D(plate, Translate3d(V3{0, -0.5 * w, 0}))
+	plate1 := Transform3D(plate, Translate3d(V3{0, 0.5 * w, 0}))
+
+	leaf0 := Difference3D(plate0, Union3D(spaces[1]...))
+	leaf0 = Union3D(leaf0, Union3D(knuckles[0]...), pin)
+	leaf1 := Difference3D(plate1, Union3D(spaces[0]...))
+	leaf1 = Union3D(leaf1, Union3D(knuckles[1]...))
+	leaf1 = Difference3D(leaf1, hole)
+
+	return []SDF3{leaf0, leaf1}, nil
+}
+
+// Hinge3D returns the two leaves of a print-in-place hinge (panic on error).
+func Hinge3D(k *HingeParms) []SDF3 {
+	s, err := NewHinge3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,230 @@
+//-----------------------------------------------------------------------------
+/*
+
+Knurling
+
+Straight, diagonal and diamond knurls on a surface of revolution.
+
+The surface is given by a 2D profile as for Revolve3D (x is the radial
+distance, y is the distance along the z-axis). The grooves are 90 degree
+V-grooves cut to a constant depth normal to the surface, so tapered and
+curved knobs are knurled with the same depth as cylinders.
+
+straight: grooves parallel to the axis
+diagonal: helical grooves at the helix angle
+diamond: left and right hand helical grooves
+
+The knurl pitch is the distance between grooves (normal to the grooves)
+at the largest radius of the profile. The number of grooves is fixed, so
+the pitch reduces on smaller radii. Flat faces within the axial extent of
+the knurl are grooved as well, so limit the extent to the knurled surface.
+
+Pitch presets:
+
+din82_x: DIN 82 pitches (mm)
+ansi_x: ANSI diametral pitches, x grooves per inch of diameter (inch)
+3dp_x: coarse pitches for 3d printing (mm)
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+// Knurl Pitch Database - lookup standard knurl pitches by name
+
+type KnurlPitch struct {
+	Name  string  // name of pitch
+	Pitch float64 // pitch normal to the grooves
+	Units string  // "inch" or "mm"
+}
+
+type KnurlPitchDatabase map[string]*KnurlPitch
+
+var knurl_pitch_db = Init_KnurlPitchLookup()
+
+// MetricAdd adds a metric knurl pitch to the database.
+func (m KnurlPitchDatabase) MetricAdd(
+	name string, // name of pitch
+	pitch float64, // pitch (mm)
+) {
+	k := KnurlPitch{}
+	k.Name = name
+	k.Pitch = pitch
+	k.Units = "mm"
+	m[name] = &k
+}
+
+// ANSIAdd adds an ANSI diametral knurl pitch to the database.
+func (m KnurlPitchDatabase) ANSIAdd(
+	name string, // name of pitch
+	dp float64, // diametral pitch (grooves per inch of diameter)
+) {
+	k := KnurlPitch{}
+	k.Name = name
+	k.Pitch = PI / dp
+	k.Units = "inch"
+	m[name] = &k
+}
+
+func Init_KnurlPitchLookup() KnurlPitchDatabase {
+	m := make(KnurlPitchDatabase)
+	// DIN 82
+	m.MetricAdd("din82_0.5", 0.5)
+	m.MetricAdd("din82_0.6", 0.6)
+	m.MetricAdd("din82_0.8", 0.8)
+	m.MetricAdd("din82_1.0", 1.0)
+	m.MetricAdd("din82_1.2", 1.2)
+	m.MetricAdd("din82_1.6", 1.6)
+	// ANSI B94.6
+	m.ANSIAdd("ansi_64", 64)
+	m.ANSIAdd("ansi_80", 80)
+	m.ANSIAdd("ansi_96", 96)
+	m.ANSIAdd("ansi_128", 128)
+	m.ANSIAdd("ansi_160", 160)
+	// 3d printing
+	m.MetricAdd("3dp_fine", 1.2)
+	m.MetricAdd("3dp_medium", 1.6)
+	m.MetricAdd("3dp_coarse", 2.5)
+	return m
+}
+
+// lookup the parameters for a knurl pitch by name
+func KnurlPitchLookupErr(name string) (*KnurlPitch, error) {
+	k, ok := knurl_pitch_db[name]
+	if !ok {
+		return nil, param_error("KnurlPitchLookup", "name", name, "knurl pitch name not found")
+	}
+	return k, nil
+}
+
+// lookup the parameters for a knurl pitch by name (panic on error)
+func KnurlPitchLookup(name string) *KnurlPitch {
+	k, err := KnurlPitchLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+//-----------------------------------------------------------------------------
+// Knurled Solids of Revolution
+
+type KnurlParms struct {
+	Pattern string  // "straight", "diagonal" or "diamond"
+	Pitch   float64 // groove pitch at the largest radius (see KnurlPitchLookup)
+	Depth   float64 // groove depth normal to the surface (0 for 0.3 * pitch)
+	Angle   float64 // helix angle of diagonal/diamond grooves (radians, 0 for 30 degrees)
+	Min     float64 // start of the knurl on the z-axis
+	Max     float64 // end of the knurl on the z-axis (Min == Max for the whole profile)
+}
+
+type KnurlSDF3 struct {
+	sdf     SDF2    // profile of the surface of revolution
+	pattern string  // knurl pattern
+	n       float64 // number of grooves
+	radius  float64 // reference radius for the helix
+	depth   float64 // groove depth
+	angle   float64 // helix angle
+	z0, z1  float64 // axial extent of the knurl
+	bb      Box3
+}
+
+// NewKnurledRevolve3D returns a knurled solid of revolution.
+func NewKnurledRevolve3D(profile SDF2, k *KnurlParms) (SDF3, error) {
+	if k.Pitch <= 0 {
+		return nil, param_error("KnurledRevolve3D", "Pitch", k.Pitch, "must be > 0")
+	}
+	depth := k.Depth
+	if depth == 0 {
+		depth = 0.3 * k.Pitch
+	}
+	if depth < 0 || depth > 0.5*k.Pitch {
+		return nil, param_error("KnurledRevolve3D", "Depth", k.Depth, "must be > 0 and <= 0.5 * Pitch")
+	}
+	angle := 0.0
+	switch k.Pattern {
+	case "straight":
+	case "diagonal", "diamond":
+		angle = k.Angle
+		if angle == 0 {
+			angle = DtoR(30)
+		}
+		if angle < 0 || angle >= DtoR(90) {
+			return nil, param_error("KnurledRevolve3D", "Angle", k.Angle, "must be > 0 and < 90 degrees")
+		}
+	default:
+		return nil, param_error("KnurledRevolve3D", "Pattern", k.Pattern, "unknown knurl pattern")
+	}
+	bb := profile.BoundingBox()
+	if bb.Max.X <= 0 {
+		return nil, param_error("KnurledRevolve3D", "profile", bb.Max.X, "maximum radius must be > 0")
+	}
+	if k.Min > k.Max {
+		return nil, param_error("KnurledRevolve3D", "Min", k.Min, "must be <= Max")
+	}
+
+	s := KnurlSDF3{}
+	s.sdf = profile
+	s.pattern = k.Pattern
+	s.radius = bb.Max.X
+	s.depth = depth
+	s.angle = angle
+	// the grooves must meet around the circumference
+	s.n = Max(1, math.Floor(TAU*s.radius*math.Cos(angle)/k.Pitch+0.5))
+	s.z0, s.z1 = k.Min, k.Max
+	if s.z0 == s.z1 {
+		s.z0, s.z1 = bb.Min.Y, bb.Max.Y
+	}
+	s.bb = Revolve3D(profile).BoundingBox()
+	return &s, nil
+}
+
+// KnurledRevolve3D returns a knurled solid of revolution (panic on error).
+func KnurledRevolve3D(profile SDF2, k *KnurlParms) SDF3 {
+	s, err := NewKnurledRevolve3D(profile, k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// groove returns the depth of cut for a groove at the helix angle.
+func (s *KnurlSDF3) groove(x, phi, z, angle float64) float64 {
+	// groove phase, the grooves are at the half integers
+	u := s.n * (phi - z*math.Tan(angle)/s.radius) / TAU
+	f := Abs(u - math.Floor(u+0.5))
+	// pitch normal to the grooves at this radius
+	p := TAU * x * math.Cos(angle) / s.n
+	// 90 degree V-groove
+	return Max(0, f*p-(0.5*p-s.depth))
+}
+
+// Return the minimum distance to a knurled solid of revolution.
+func (s *KnurlSDF3) Evaluate(p V3) float64 {
+	x := math.Sqrt(p.X*p.X + p.Y*p.Y)
+	phi := math.Atan2(p.Y, p.X)
+	d := s.sdf.Evaluate(V2{x, p.Z})
+	var g float64
+	switch s.pattern {
+	case "straight":
+		g = s.groove(x, phi, p.Z, 0)
+	case "diagonal":
+		g = s.groove(x, phi, p.Z, s.angle)
+	case "diamond":
+		g = Max(s.groove(x, phi, p.Z, s.angle), s.groove(x, phi, p.Z, -s.angle))
+	}
+	// 45 degree run-out at the ends of the knurl
+	g = Min(g, Max(0, Min(p.Z-s.z0, s.z1-p.Z)))
+	// the groove flanks are at 45 degrees to the surface
+	return (d + g) / math.Sqrt2
+}
+
+// Return the bounding box for a knurled solid of revolution.
+func (s *KnurlSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,208 @@
+//-----------------------------------------------------------------------------
+/*
+
+Planetary Gearsets
+
+A planetary gearset has a central sun gear, N planet gears meshing with the
+sun and an internal ring gear meshing with the planets. The planets are held
+by a carrier plate.
+
+With the ring fixed, the sun as the input and the carrier as the output the
+reduction ratio is 1 + ring_teeth/sun_teeth.
+
+Assembly conditions for equally spaced planets:
+
+1) ring_teeth = sun_teeth + 2 * planet_teeth (the gears are coaxial)
+2) (sun_teeth + ring_teeth) / N is an integer (the planets can be meshed)
+3) adjacent planets must not touch
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import (
+	"fmt"
+	"math"
+)
+
+//-----------------------------------------------------------------------------
+
+type PlanetaryParms struct {
+	Ratio            float64 // desired reduction ratio (fixed ring, sun input, carrier output)
+	NumberPlanets    int     // number of planet gears
+	Module           float64 // pitch circle diameter / number of gear teeth
+	PressureAngle    float64 // gear pressure angle (radians)
+	Backlash         float64 // backlash expressed as per-tooth distance at pitch circumference
+	Clearance        float64 // additional root clearance
+	MinTeeth         int     // minimum number of teeth for the sun and planets (default 12)
+	MaxTeeth         int     // maximum number of teeth for the ring (default 200)
+	RimWidth         float64 // width of the ring gear rim
+	FaceWidth        float64 // gear face width (3d parts)
+	CarrierThickness float64 // thickness of the carrier plate (3d parts)
+	ShaftDiameter    float64 // diameter of the sun/carrier shaft hole (0 for none)
+	PinDiameter      float64 // diameter of the planet pins/holes (0 for none)
+	Facets           int     // number of facets for involute flank
+}
+
+type PlanetaryGears struct {
+	SunTeeth       int     // number of sun gear teeth
+	PlanetTeeth    int     // number of planet gear teeth
+	RingTeeth      int     // number of ring gear teeth
+	Ratio          float64 // actual reduction ratio
+	CenterDistance float64 // sun to planet center distance
+	Planet         V2Set   // planet center positions
+	// 2d profiles, positioned about the sun center
+	Sun     SDF2
+	Planets []SDF2
+	Ring    SDF2
+	Carrier SDF2
+	// 3d parts, gears centered on z = 0 with the carrier below
+	Sun3D     SDF3
+	Planets3D []SDF3
+	Ring3D    SDF3
+	Carrier3D SDF3
+}
+
+// planetary_check returns nil if the tooth counts give a valid planetary gearset.
+func planetary_check(sun, planet, n int, k *PlanetaryParms) error {
+	ring := sun + 2*planet
+	if (sun+ring)%n != 0 {
+		return fmt.Errorf("(sun + ring teeth) must be a multiple of the number of planets")
+	}
+	// adjacent planet tip circles must not touch
+	if float64(sun+planet)*math.Sin(PI/float64(n)) <= float64(planet+2) {
+		return fmt.Errorf("adjacent planets collide")
+	}
+	addendum := InternalGearAddendum(planet, ring, k.Module, k.PressureAngle)
+	return InternalGearCheck(planet, ring, k.Module, k.PressureAngle, addendum)
+}
+
+// solid_gear returns an involute gear with the root circle filled in.
+func solid_gear(number_teeth int, k *PlanetaryParms) SDF2 {
+	gear := InvoluteGear(number_teeth, k.Module, k.PressureAngle, k.Backlash, k.Clearance, 0, k.Facets)
+	root_radius := float64(number_teeth)*k.Module/2.0 - (k.Module + k.Clearance)
+	return Union2D(gear, Circle2D(root_radius))
+}
+
+// MakePlanetaryGears designs a planetary gearset for a desired ratio.
+// The tooth counts closest to the desired ratio that satisfy the assembly
+// conditions are used.
+func MakePlanetaryGears(k *PlanetaryParms) (*PlanetaryGears, error) {
+	if k.Ratio <= 2 {
+		return nil, fmt.Errorf("invalid ratio, must be > 2")
+	}
+	if k.NumberPlanets < 1 {
+		return nil, fmt.Errorf("invalid number of planets, must be >= 1")
+	}
+	if k.Module <= 0 {
+		return nil, fmt.Errorf("invalid module, must be > 0")
+	}
+	if k.PressureAngle <= 0 || k.PressureAngle >= DtoR(45) {
+		return nil, fmt.Errorf("invalid pressure angle")
+	}
+	if k.Facets <= 0 {
+		return nil, fmt.Errorf("invalid number of facets, must be > 0")
+	}
+	if k.RimWidth <= 0 || k.FaceWidth <= 0 || k.CarrierThickness <= 0 {
+		return nil, fmt.Errorf("invalid dimensions, must be > 0")
+	}
+	min_teeth := k.MinTeeth
+	if min_teeth == 0 {
+		min_teeth = 12
+	}
+	max_teeth := k.MaxTeeth
+	if max_teeth == 0 {
+		max_teeth = 200
+	}
+
+	// search for the tooth counts closest to the desired ratio
+	g := PlanetaryGears{}
+	best := math.MaxFloat64
+	for sun := min_teeth; sun <= max_teeth; sun++ {
+		for planet := min_teeth; sun+2*planet <= max_teeth; planet++ {
+			ring := sun + 2*planet
+			ratio := 1 + float64(ring)/float64(sun)
+			// prefer the smallest gearset for equal error
+			if Abs(ratio-k.Ratio) >= best {
+				continue
+			}
+			if planetary_check(sun, planet, k.NumberPlanets, k) != nil {
+				continue
+			}
+			best = Abs(ratio - k.Ratio)
+			g.SunTeeth = sun
+			g.PlanetTeeth = planet
+			g.RingTeeth = ring
+			g.Ratio = ratio
+		}
+	}
+	if g.SunTeeth == 0 {
+		return nil, fmt.Errorf("no valid planetary gearset for ratio %f with %d planets", k.Ratio, k.NumberPlanets)
+	}
+
+	zs := float64(g.SunTeeth)
+	zp := float64(g.PlanetTeeth)
+	zr := float64(g.RingTeeth)
+	g.CenterDistance = (zs + zp) * k.Module / 2.0
+
+	// sun gear: a tooth is centered on the x-axis
+	sun := solid_gear(g.SunTeeth, k)
+	if k.ShaftDiameter > 0 {
+		sun = Difference2D(sun, Circle2D(0.5*k.ShaftDiameter))
+	}
+
+	// planet gear: rotate to put a tooth space facing the sun
+	planet := solid_gear(g.PlanetTeeth, k)
+	if k.PinDiameter > 0 {
+		planet = Difference2D(planet, Circle2D(0.5*k.PinDiameter))
+	}
+	phase := PI - PI/zp
+
+	// ring gear: a tooth space is centered on the x-axis.
+	// The first planet has a tooth facing the ring if it has an odd number of teeth.
+	addendum := InternalGearAddendum(g.PlanetTeeth, g.RingTeeth, k.Module, k.PressureAngle)
+	ring := InternalInvoluteGear(g.RingTeeth, k.Module, k.PressureAngle, k.Backlash, k.Clearance, addendum, k.RimWidth, k.Facets)
+	if g.PlanetTeeth%2 == 0 {
+		ring = Transform2D(ring, Rotate2d(PI/zr))
+	}
+
+	// position the planets
+	g.Planets = make([]SDF2, k.NumberPlanets)
+	g.Planet = make(V2Set, k.NumberPlanets)
+	for i := range g.Planets {
+		theta := TAU * float64(i) / float64(k.NumberPlanets)
+		// The sun tooth phase at theta determines the planet rotation.
+		rotate := phase + theta + theta*zs/zp
+		g.Planet[i] = PolarToXY(g.CenterDistance, theta)
+		m := Translate2d(g.Planet[i]).Mul(Rotate2d(rotate))
+		g.Planets[i] = Transform2D(planet, m)
+	}
+	g.Sun = sun
+	g.Ring = ring
+
+	// carrier plate
+	rp := zp * k.Module / 2.0
+	carrier := Circle2D(g.CenterDistance + 0.5*rp)
+	if k.PinDiameter > 0 {
+		carrier = Difference2D(carrier, MultiCircle2D(0.5*k.PinDiameter, g.Planet))
+	}
+	if k.ShaftDiameter > 0 {
+		carrier = Difference2D(carrier, Circle2D(0.5*k.ShaftDiameter))
+	}
+	g.Carrier = carrier
+
+	// 3d parts
+	g.Sun3D = Extrude3D(g.Sun, k.FaceWidth)
+	g.Ring3D = Extrude3D(g.Ring, k.FaceWidth)
+	g.Planets3D = make([]SDF3, k.NumberPlanets)
+	for i := range g.Planets {
+		g.Planets3D[i] = Extrude3D(g.Planets[i], k.FaceWidth)
+	}
+	z := -0.5 * (k.FaceWidth + k.CarrierThickness)
+	g.Carrier3D = Transform3D(Extrude3D(g.Carrier, k.CarrierThickness), Translate3d(V3{0, 0, z}))
+
+	return &g, nil
+}
+
+//-----------------------------------------------------------------------------
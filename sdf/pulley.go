@@ -0,0 +1,293 @@
+//-----------------------------------------------------------------------------
+/*
+
+Timing Belt Pulleys
+
+Pulleys are made by cutting belt tooth shaped grooves into a disk. The
+belt tooth profiles are approximations of the standard profiles built from
+the published belt dimensions:
+
+"trapezoid" belts (MXL, XL, T2.5, T5) have straight flanks at a given
+included angle.
+
+"round" belts (GT2, HTD) have a semicircular tooth of a given radius.
+
+The pitch line of the belt (the tension cords) is outside the pulley by
+the pitch line differential (PLD), so:
+
+pitch diameter = number_teeth * pitch / PI
+outside diameter = pitch diameter - 2 * PLD
+
+Printed pulleys usually need some extra groove clearance.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+// Belt Database - lookup standard timing belt profiles by name
+
+type BeltParameters struct {
+	Name        string  // name of the belt profile
+	Shape       string  // tooth shape: "trapezoid" or "round"
+	Pitch       float64 // tooth to tooth distance
+	PLD         float64 // pitch line differential
+	ToothHeight float64 // height of the belt tooth
+	ToothWidth  float64 // width of the belt tooth at the land (trapezoid)
+	ToothAngle  float64 // included angle of the tooth flanks (trapezoid, radians)
+	ToothRadius float64 // radius of the belt tooth (round)
+	Thickness   float64 // total belt thickness (back to tooth tip)
+}
+
+type BeltDatabase map[string]*BeltParameters
+
+var belt_db = Init_BeltLookup()
+
+// TrapezoidAdd adds a trapezoidal tooth belt to the belt database.
+func (m BeltDatabase) TrapezoidAdd(
+	name string, // belt name
+	pitch float64, // tooth to tooth distance
+	pld float64, // pitch line differential
+	height float64, // tooth height
+	width float64, // tooth width at the land
+	angle float64, // included angle of the tooth flanks (degrees)
+	thickness float64, // total belt thickness
+) {
+	b := BeltParameters{}
+	b.Name = name
+	b.Shape = "trapezoid"
+	b.Pitch = pitch
+	b.PLD = pld
+	b.ToothHeight = height
+	b.ToothWidth = width
+	b.ToothAngle = DtoR(angle)
+	b.Thickness = thickness
+	m[name] = &b
+}
+
+// RoundAdd adds a curvilinear (round) tooth belt to the belt database.
+func (m BeltDatabase) RoundAdd(
+	name string, // belt name
+	pitch float64, // tooth to tooth distance
+	pld float64, // pitch line differential
+	height float64, // tooth height
+	radius float64, // tooth radius
+	thickness float64, // total belt thickness
+) {
+	b := BeltParameters{}
+	b.Name = name
+	b.Shape = "round"
+	b.Pitch = pitch
+	b.PLD = pld
+	b.ToothHeight = height
+	b.ToothRadius = radius
+	b.Thickness = thickness
+	m[name] = &b
+}
+
+func Init_BeltLookup() BeltDatabase {
+	m := make(BeltDatabase)
+	// trapezoidal (inch)
+	m.TrapezoidAdd("MXL", 2.032, 0.254, 0.51, 1.14, 40, 1.14)
+	m.TrapezoidAdd("XL", 5.08, 0.254, 1.27, 2.57, 50, 2.3)
+	// trapezoidal (metric)
+	m.TrapezoidAdd("T2.5", 2.5, 0.3, 0.7, 1.5, 40, 1.3)
+	m.TrapezoidAdd("T5", 5, 0.5, 1.2, 2.65, 40, 2.2)
+	// curvilinear
+	m.RoundAdd("GT2_2mm", 2, 0.254, 0.75, 0.555, 1.38)
+	m.RoundAdd("GT2_3mm", 3, 0.381, 1.14, 0.85, 2.41)
+	m.RoundAdd("HTD_3M", 3, 0.381, 1.22, 0.85, 2.4)
+	m.RoundAdd("HTD_5M", 5, 0.5715, 2.06, 1.49, 3.8)
+	return m
+}
+
+// lookup the parameters for a belt by name
+func BeltLookupErr(name string) (*BeltParameters, error) {
+	b, ok := belt_db[name]
+	if !ok {
+		return nil, param_error("BeltLookup", "name", name, "belt name not found")
+	}
+	return b, nil
+}
+
+// lookup the parameters for a belt by name (panic on error)
+func BeltLookup(name string) *BeltParameters {
+	b, err := BeltLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// PitchDiameter returns the pitch diameter of a pulley for this belt.
+func (b *BeltParameters) PitchDiameter(number_teeth int) float64 {
+	return float64(number_teeth) * b.Pitch / PI
+}
+
+// OutsideDiameter returns the outside diameter of a pulley for this belt.
+func (b *BeltParameters) OutsideDiameter(number_teeth int) float64 {
+	return b.PitchDiameter(number_teeth) - 2.0*b.PLD
+}
+
+// Tooth2D returns the 2D profile of a belt tooth.
+// The tooth land is on the x-axis and the tooth points down the -y axis.
+// The profile extends above the land (by the tooth height) so it can be
+// unioned with a belt back or cut cleanly from a pulley.
+func (b *BeltParameters) Tooth2D() SDF2 {
+	h := b.ToothHeight
+	switch b.Shape {
+	case "trapezoid":
+		w := 0.5 * b.ToothWidth
+		x := w - h*math.Tan(0.5*b.ToothAngle)
+		if x <= 0 {
+			panic("invalid belt tooth")
+		}
+		return Polygon2D([]V2{{-x, -h}, {x, -h}, {w, 0}, {w, h}, {-w, h}, {-w, 0}})
+	case "round":
+		r := b.ToothRadius
+		if r > h {
+			panic("invalid belt tooth")
+		}
+		c := Transform2D(Circle2D(r), Translate2d(V2{0, r - h}))
+		l := 2.0*h - r
+		s := Transform2D(Box2D(V2{2 * r, l}, 0), Translate2d(V2{0, r - h + 0.5*l}))
+		return Union2D(c, s)
+	}
+	panic("invalid belt tooth shape")
+}
+
+//-----------------------------------------------------------------------------
+// Pulleys
+
+type PulleyParms struct {
+	Belt            string  // belt name (see BeltLookup)
+	NumberTeeth     int     // number of pulley teeth
+	BeltWidth       float64 // width of the toothed section
+	Clearance       float64 // additional groove clearance
+	Flanges         string  // "none", "top", "bottom" or "both"
+	FlangeHeight    float64 // radial height of the flanges above the outside diameter
+	FlangeThickness float64 // thickness of the flanges
+	Bore            float64 // bore diameter (0 for none)
+}
+
+// Pulley2D returns the 2D profile of a timing belt pulley.
+func Pulley2D(
+	belt *BeltParameters, // belt parameters
+	number_teeth int, // number of pulley teeth
+	clearance float64, // additional groove clearance
+) SDF2 {
+	if number_teeth < 2 {
+		panic("invalid number of teeth")
+	}
+	r := 0.5 * belt.OutsideDiameter(number_teeth)
+	if r <= belt.ToothHeight+clearance {
+		panic("not enough teeth for this belt")
+	}
+	// a groove centered on the x-axis
+	groove := belt.Tooth2D()
+	if clearance > 0 {
+		groove = Offset2D(groove, clearance)
+	}
+	groove = Transform2D(groove, Translate2d(V2{r, 0}).Mul(Rotate2d(-PI/2)))
+	return Difference2D(Circle2D(r), RotateCopy2D(groove, number_teeth))
+}
+
+// Pulley3D returns a timing belt pulley with its axis on the z-axis.
+// The toothed section is centered on the origin.
+func Pulley3D(k *PulleyParms) SDF3 {
+	belt := BeltLookup(k.Belt)
+	if k.BeltWidth <= 0 {
+		panic("invalid belt width")
+	}
+	s := Extrude3D(Pulley2D(belt, k.NumberTeeth, k.Clearance), k.BeltWidth)
+	// flanges
+	top, bottom := false, false
+	switch k.Flanges {
+	case "", "none":
+	case "top":
+		top = true
+	case "bottom":
+		bottom = true
+	case "both":
+		top = true
+		bottom = true
+	default:
+		panic("invalid flanges")
+	}
+	if top || bottom {
+		if k.FlangeHeight <= 0 || k.FlangeThickness <= 0 {
+			panic("invalid flange dimensions")
+		}
+		r := 0.5*belt.OutsideDiameter(k.NumberTeeth) + k.FlangeHeight
+		flange := Cylinder3D(k.FlangeThickness, r, 0)
+		z := 0.5 * (k.BeltWidth + k.FlangeThickness)
+		if top {
+			s = Union3D(s, Transform3D(flange, Translate3d(V3{0, 0, z})))
+		}
+		if bottom {
+			s = Union3D(s, Transform3D(flange, Translate3d(V3{0, 0, -z})))
+		}
+	}
+	// bore
+	if k.Bore > 0 {
+		bb := s.BoundingBox()
+		bore := Cylinder3D(bb.Size().Z, 0.5*k.Bore, 0)
+		bore = Transform3D(bore, Translate3d(V3{0, 0, bb.Center().Z}))
+		s = Difference3D(s, bore)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+// Belts
+
+type BeltSDF2 struct {
+	tooth  SDF2    // belt tooth profile
+	pitch  float64 // tooth to tooth distance
+	y0, y1 float64 // y-extent of the belt back
+	length float64 // half the total belt length
+	bb     Box2    // bounding box
+}
+
+// Belt2D returns a rack style profile for a straight length of timing belt.
+// The belt pitch line is on the x-axis and the teeth point down the -y axis.
+// A tooth is centered on the y-axis.
+func Belt2D(
+	belt *BeltParameters, // belt parameters
+	number_teeth float64, // number of belt teeth
+) SDF2 {
+	s := BeltSDF2{}
+	s.tooth = Transform2D(belt.Tooth2D(), Translate2d(V2{0, -belt.PLD}))
+	s.pitch = belt.Pitch
+	s.y0 = -belt.PLD
+	s.y1 = belt.Thickness - belt.ToothHeight - belt.PLD
+	if s.y1 <= s.y0 {
+		panic("invalid belt thickness")
+	}
+	s.length = belt.Pitch * number_teeth / 2.0
+	s.bb = Box2{V2{-s.length, s.y0 - belt.ToothHeight}, V2{s.length, s.y1}}
+	return &s
+}
+
+// Evaluate returns the minimum distance to the belt.
+func (s *BeltSDF2) Evaluate(p V2) float64 {
+	// map p.X back to the [-half_pitch, half_pitch) domain
+	p0 := V2{SawTooth(p.X, s.pitch), p.Y}
+	// the belt back and the tooth
+	d0 := Max(s.y0-p.Y, p.Y-s.y1)
+	d0 = Min(d0, Max(s.tooth.Evaluate(p0), p.Y-s.y1))
+	// create a region for the belt length
+	d1 := Abs(p.X) - s.length
+	// return the intersection
+	return Max(d0, d1)
+}
+
+// BoundingBox returns the bounding box for the belt.
+func (s *BeltSDF2) BoundingBox() Box2 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,274 @@
+//-----------------------------------------------------------------------------
+/*
+
+Rack Panels
+
+Front panels for Eurorack modules and 19 inch rack equipment. The panel
+dimensions and mounting holes are worked out from the standards. Panels are
+centered on the origin, component positions are relative to the panel
+center. All dimensions are in mm.
+
+Eurorack (Doepfer A-100):
+
+3U panel height 128.5, mounting holes 3 from the top and bottom edges.
+1U (Intellijel) panel height 39.65, mounting holes 3 from the top and bottom edges.
+The width is HP * 5.08 less a fitting allowance. The mounting holes are 7.5
+from the left edge, panels of 10HP and wider have a second set of holes
+(HP - 3) * 5.08 to the right of the first.
+
+19 inch rack (EIA-310):
+
+1U = 44.45, the panel height is n * 1U less 0.79 (1/32 inch).
+The panel width is 482.6 and the mounting holes are 465.1 apart.
+Each U has holes 6.35 and 38.1 above the bottom of the U.
+
+The mounting holes can be slotted horizontally to ease alignment.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+//-----------------------------------------------------------------------------
+// Panel Component Database - lookup panel hole sizes by name
+
+type PanelComponentParameters struct {
+	Name     string  // name of component
+	Diameter float64 // panel hole diameter
+	Keepout  float64 // diameter of the panel area used by the nut/knob
+}
+
+type PanelComponentDatabase map[string]*PanelComponentParameters
+
+var panel_component_db = Init_PanelComponentLookup()
+
+// PanelComponentAdd adds a component to the panel component database (dimensions in mm).
+func (m PanelComponentDatabase) PanelComponentAdd(
+	name string, // component name
+	diameter float64, // hole diameter
+	keepout float64, // keepout diameter
+) {
+	k := PanelComponentParameters{}
+	k.Name = name
+	k.Diameter = diameter
+	k.Keepout = keepout
+	m[name] = &k
+}
+
+func Init_PanelComponentLookup() PanelComponentDatabase {
+	m := make(PanelComponentDatabase)
+	m.PanelComponentAdd("jack_3.5", 6.0, 8.0)      // Thonkiconn
+	m.PanelComponentAdd("jack_6.35", 9.5, 14.0)    // 1/4 inch
+	m.PanelComponentAdd("pot_9mm", 7.0, 12.0)      // Alpha 9mm
+	m.PanelComponentAdd("pot_16mm", 7.5, 17.0)     // Alpha 16mm
+	m.PanelComponentAdd("pot_24mm", 9.5, 25.0)     // Alpha 24mm
+	m.PanelComponentAdd("toggle_mini", 6.2, 10.0)  // mini toggle switch
+	m.PanelComponentAdd("button_tl1105", 3.5, 7.0) // tactile switch cap
+	m.PanelComponentAdd("led_3mm", 3.2, 5.0)
+	m.PanelComponentAdd("led_5mm", 5.2, 7.0)
+	return m
+}
+
+// lookup the parameters for a panel component by name
+func PanelComponentLookupErr(name string) (*PanelComponentParameters, error) {
+	k, ok := panel_component_db[name]
+	if !ok {
+		return nil, param_error("PanelComponentLookup", "name", name, "panel component name not found")
+	}
+	return k, nil
+}
+
+// lookup the parameters for a panel component by name (panic on error)
+func PanelComponentLookup(name string) *PanelComponentParameters {
+	k, err := PanelComponentLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+type PanelComponent struct {
+	Component string // name of component (see PanelComponentLookup)
+	Position  V2     // position relative to the panel center
+}
+
+// panel_holes returns the component holes for a panel.
+func panel_holes(fn string, size V2, components []PanelComponent) (SDF2, error) {
+	var holes []SDF2
+	for _, c := range components {
+		k, err := PanelComponentLookupErr(c.Component)
+		if err != nil {
+			return nil, err
+		}
+		r := 0.5 * Max(k.Diameter, k.Keepout)
+		if Abs(c.Position.X)+r > 0.5*size.X || Abs(c.Position.Y)+r > 0.5*size.Y {
+			return nil, param_error(fn, "Components", c.Component, "component is not on the panel")
+		}
+		holes = append(holes, Transform2D(Circle2D(0.5*k.Diameter), Translate2d(c.Position)))
+	}
+	return Union2D(holes...), nil
+}
+
+// mounting_hole returns a mounting hole, slotted horizontally for a non-zero slot length.
+func mounting_hole(diameter, slot float64) SDF2 {
+	if slot > 0 {
+		return Line2D(slot, 0.5*diameter)
+	}
+	return Circle2D(0.5 * diameter)
+}
+
+//-----------------------------------------------------------------------------
+// Eurorack Panels
+
+const eurorack_hp = 5.08
+
+// EurorackWidth returns the width of a Eurorack panel.
+func EurorackWidth(hp int) float64 {
+	// less a fitting allowance (close to the Doepfer widths)
+	return float64(hp)*eurorack_hp - 0.3
+}
+
+type EurorackParms struct {
+	Format       string           // "3U" or "1U"
+	HP           int              // panel width in horizontal pitch units
+	HoleDiameter float64          // mounting hole diameter (0 for 3.2)
+	SlotLength   float64          // extra length of the mounting slots (0 for round holes)
+	Components   []PanelComponent // jacks, pots, etc.
+}
+
+// NewEurorackPanel2D returns a Eurorack module panel.
+func NewEurorackPanel2D(k *EurorackParms) (SDF2, error) {
+	var h float64
+	switch k.Format {
+	case "3U":
+		h = 128.5
+	case "1U":
+		h = 39.65
+	default:
+		return nil, param_error("EurorackPanel2D", "Format", k.Format, "must be 3U or 1U")
+	}
+	if k.HP < 1 {
+		return nil, param_error("EurorackPanel2D", "HP", k.HP, "must be >= 1")
+	}
+	if k.HoleDiameter < 0 {
+		return nil, param_error("EurorackPanel2D", "HoleDiameter", k.HoleDiameter, "must be >= 0")
+	}
+	if k.SlotLength < 0 {
+		return nil, param_error("EurorackPanel2D", "SlotLength", k.SlotLength, "must be >= 0")
+	}
+	d := k.HoleDiameter
+	if d == 0 {
+		d = 3.2
+	}
+	size := V2{EurorackWidth(k.HP), h}
+
+	// mounting holes
+	var xs []float64
+	if k.HP < 3 {
+		// narrow panels have centered holes
+		xs = []float64{0}
+	} else {
+		x0 := 7.5 - 0.5*size.X
+		xs = []float64{x0}
+		if k.HP >= 10 {
+			xs = append(xs, x0+float64(k.HP-3)*eurorack_hp)
+		}
+	}
+	for _, x := range xs {
+		if Abs(x)+0.5*(k.SlotLength+d) > 0.5*size.X {
+			return nil, param_error("EurorackPanel2D", "SlotLength", k.SlotLength, "mounting slot is not on the panel")
+		}
+	}
+	y := 0.5*h - 3.0
+	hole := mounting_hole(d, k.SlotLength)
+	var holes []SDF2
+	for _, x := range xs {
+		holes = append(holes, Transform2D(hole, Translate2d(V2{x, y})))
+		holes = append(holes, Transform2D(hole, Translate2d(V2{x, -y})))
+	}
+
+	// components
+	c, err := panel_holes("EurorackPanel2D", size, k.Components)
+	if err != nil {
+		return nil, err
+	}
+	return Difference2D(Box2D(size, 0), Union2D(append(holes, c)...)), nil
+}
+
+// EurorackPanel2D returns a Eurorack module panel (panic on error).
+func EurorackPanel2D(k *EurorackParms) SDF2 {
+	s, err := NewEurorackPanel2D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+// 19 Inch Rack Panels
+
+const rack_u = 44.45
+
+// RackPanelHeight returns the height of a 19 inch rack panel.
+func RackPanelHeight(u int) float64 {
+	return float64(u)*rack_u - 0.79
+}
+
+type RackParms struct {
+	U            int              // panel height in rack units
+	HoleDiameter float64          // mounting hole diameter (0 for 6.5)
+	SlotLength   float64          // extra length of the mounting slots (0 for round holes)
+	Components   []PanelComponent // jacks, pots, etc.
+}
+
+// NewRackPanel2D returns a 19 inch rack panel.
+func NewRackPanel2D(k *RackParms) (SDF2, error) {
+	if k.U < 1 {
+		return nil, param_error("RackPanel2D", "U", k.U, "must be >= 1")
+	}
+	if k.HoleDiameter < 0 {
+		return nil, param_error("RackPanel2D", "HoleDiameter", k.HoleDiameter, "must be >= 0")
+	}
+	if k.SlotLength < 0 {
+		return nil, param_error("RackPanel2D", "SlotLength", k.SlotLength, "must be >= 0")
+	}
+	d := k.HoleDiameter
+	if d == 0 {
+		d = 6.5
+	}
+	size := V2{482.6, RackPanelHeight(k.U)}
+	x := 0.5 * 465.1
+	if x+0.5*(k.SlotLength+d) > 0.5*size.X {
+		return nil, param_error("RackPanel2D", "SlotLength", k.SlotLength, "mounting slot is not on the panel")
+	}
+
+	// mounting holes, the bottom of the first U is below the panel
+	y0 := -0.5 * float64(k.U) * rack_u
+	hole := mounting_hole(d, k.SlotLength)
+	var holes []SDF2
+	for i := 0; i < k.U; i++ {
+		for _, y := range []float64{6.35, 38.1} {
+			p := V2{x, y0 + float64(i)*rack_u + y}
+			holes = append(holes, Transform2D(hole, Translate2d(p)))
+			holes = append(holes, Transform2D(hole, Translate2d(V2{-p.X, p.Y})))
+		}
+	}
+
+	// components between the mounting holes
+	c, err := panel_holes("RackPanel2D", V2{2.0*x - (k.SlotLength + d), size.Y}, k.Components)
+	if err != nil {
+		return nil, err
+	}
+	return Difference2D(Box2D(size, 0), Union2D(append(holes, c)...)), nil
+}
+
+// RackPanel2D returns a 19 inch rack panel (panic on error).
+func RackPanel2D(k *RackParms) SDF2 {
+	s, err := NewRackPanel2D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
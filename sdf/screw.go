@@ -11,6 +11,10 @@ the x-axis as the screw axis. Most thread profiles are symmetric about the y-axi
 but a few aren't (E.g. buttress threads) so in general we build the profile of
 an entire pitch period.
 
+Tapered threads (NPT, BSPT) have a 1:16 taper on the diameter. The thread
+database gives the major radius at the gauge plane and TaperedScrew3D makes
+the radius grow along the +z axis.
+
 This code doesn't deal with thread tolerancing. If you want threads to fit properly
 the radius of the thread will need to be tweaked (+/-) to give internal/external thread
 clearance.
@@ -31,12 +35,19 @@ type ThreadParameters struct {
 	Pitch         float64 // thread to thread distance of screw
 	Hex_Flat2Flat float64 // hex head flat to flat distance
 	Units         string  // "inch" or "mm"
+	Form          string  // thread form: "iso", "uts", "whitworth", "npt" or "tr"
+	MinorRadius   float64 // basic minor radius of the internal thread
+	TapDrill      float64 // tap drill diameter
+	Taper         float64 // diameter change per unit length (0 for parallel threads)
 }
 
 type ThreadDatabase map[string]*ThreadParameters
 
 var thread_db = Init_ThreadLookup()
 
+// basic thread depth (major - minor diameter) in pitches for ISO/UTS internal threads
+const iso_minor = 1.082532
+
 // UTSAdd adds a Unified Thread Standard to the thread database.
 func (m ThreadDatabase) UTSAdd(
 	name string, // thread name
@@ -50,6 +61,9 @@ func (m ThreadDatabase) UTSAdd(
 	t.Pitch = 1.0 / tpi
 	t.Hex_Flat2Flat = hex_f2f
 	t.Units = "inch"
+	t.Form = "uts"
+	t.MinorRadius = t.Radius - 0.5*iso_minor*t.Pitch
+	t.TapDrill = diameter - t.Pitch
 	m[name] = &t
 }
 
@@ -66,6 +80,69 @@ func (m ThreadDatabase) ISOAdd(
 	t.Pitch = pitch
 	t.Hex_Flat2Flat = hex_f2f
 	t.Units = "mm"
+	t.Form = "iso"
+	t.MinorRadius = t.Radius - 0.5*iso_minor*t.Pitch
+	t.TapDrill = diameter - t.Pitch
+	m[name] = &t
+}
+
+// TrAdd adds an ISO trapezoidal (Tr) thread to the thread database.
+func (m ThreadDatabase) TrAdd(
+	name string, // thread name
+	diameter float64, // screw major diameter
+	pitch float64, // thread pitch
+) {
+	t := ThreadParameters{}
+	t.Name = name
+	t.Radius = diameter / 2.0
+	t.Pitch = pitch
+	t.Hex_Flat2Flat = -1
+	t.Units = "mm"
+	t.Form = "tr"
+	t.MinorRadius = t.Radius - 0.5*pitch
+	t.TapDrill = diameter - pitch
+	m[name] = &t
+}
+
+// BSPAdd adds a British Standard Pipe thread to the thread database.
+// Parallel (G) threads have taper = 0, tapered (R) threads have taper = 1/16.
+func (m ThreadDatabase) BSPAdd(
+	name string, // thread name
+	diameter float64, // major diameter (at the gauge plane for tapered threads)
+	tpi float64, // threads per inch
+	tap_drill float64, // tap drill diameter
+	taper float64, // diameter change per unit length
+) {
+	t := ThreadParameters{}
+	t.Name = name
+	t.Radius = diameter / 2.0
+	t.Pitch = 25.4 / tpi
+	t.Hex_Flat2Flat = -1
+	t.Units = "mm"
+	t.Form = "whitworth"
+	t.MinorRadius = t.Radius - 0.640327*t.Pitch
+	t.TapDrill = tap_drill
+	t.Taper = taper
+	m[name] = &t
+}
+
+// NPTAdd adds an American National Pipe Taper thread to the thread database.
+func (m ThreadDatabase) NPTAdd(
+	name string, // thread name
+	diameter float64, // major diameter at the gauge plane (pipe outside diameter)
+	tpi float64, // threads per inch
+	tap_drill float64, // tap drill diameter
+) {
+	t := ThreadParameters{}
+	t.Name = name
+	t.Radius = diameter / 2.0
+	t.Pitch = 1.0 / tpi
+	t.Hex_Flat2Flat = -1
+	t.Units = "inch"
+	t.Form = "npt"
+	t.MinorRadius = t.Radius - 0.8*t.Pitch
+	t.TapDrill = tap_drill
+	t.Taper = 1.0 / 16.0
 	m[name] = &t
 }
 
@@ -115,6 +192,10 @@ func Init_ThreadLookup() ThreadDatabase {
 	m.ISOAdd("M48x5", 48, 5, 75)
 	m.ISOAdd("M56x5.5", 56, 5.5, 85)
 	m.ISOAdd("M64x6", 64, 6, 95)
+	m.ISOAdd("M72x6", 72, 6, 105)
+	m.ISOAdd("M80x6", 80, 6, 115)
+	m.ISOAdd("M90x6", 90, 6, 130)
+	m.ISOAdd("M100x6", 100, 6, 145)
 	// ISO Fine
 	m.ISOAdd("M1x0.2", 1, 0.2, -1)
 	m.ISOAdd("M1.2x0.2", 1.2, 0.2, -1)
@@ -137,31 +218,133 @@ func Init_ThreadLookup() ThreadDatabase {
 	m.ISOAdd("M48x3", 48, 3, 75)
 	m.ISOAdd("M56x4", 56, 4, 85)
 	m.ISOAdd("M64x4", 64, 4, 95)
+	m.ISOAdd("M10x1", 10, 1, 17)
+	m.ISOAdd("M12x1.25", 12, 1.25, 19)
+	m.ISOAdd("M14x1.5", 14, 1.5, 22)
+	m.ISOAdd("M18x1.5", 18, 1.5, 27)
+	m.ISOAdd("M20x1.5", 20, 1.5, 30)
+	m.ISOAdd("M22x1.5", 22, 1.5, 32)
+	m.ISOAdd("M27x2", 27, 2, 41)
+	m.ISOAdd("M33x2", 33, 2, 50)
+	m.ISOAdd("M39x3", 39, 3, 60)
+	m.ISOAdd("M45x3", 45, 3, 70)
+	m.ISOAdd("M52x4", 52, 4, 80)
+	m.ISOAdd("M60x4", 60, 4, 90)
+	m.ISOAdd("M68x4", 68, 4, 100)
+	m.ISOAdd("M72x4", 72, 4, 105)
+	m.ISOAdd("M76x4", 76, 4, -1)
+	m.ISOAdd("M80x4", 80, 4, 115)
+	m.ISOAdd("M85x4", 85, 4, -1)
+	m.ISOAdd("M90x4", 90, 4, 130)
+	m.ISOAdd("M95x4", 95, 4, -1)
+	m.ISOAdd("M100x4", 100, 4, 145)
+	// ISO Trapezoidal
+	m.TrAdd("Tr8x1.5", 8, 1.5)
+	m.TrAdd("Tr8x2", 8, 2)
+	m.TrAdd("Tr10x2", 10, 2)
+	m.TrAdd("Tr12x3", 12, 3)
+	m.TrAdd("Tr14x3", 14, 3)
+	m.TrAdd("Tr16x4", 16, 4)
+	m.TrAdd("Tr18x4", 18, 4)
+	m.TrAdd("Tr20x4", 20, 4)
+	m.TrAdd("Tr24x5", 24, 5)
+	m.TrAdd("Tr28x5", 28, 5)
+	m.TrAdd("Tr32x6", 32, 6)
+	m.TrAdd("Tr36x6", 36, 6)
+	m.TrAdd("Tr40x7", 40, 7)
+	m.TrAdd("Tr44x7", 44, 7)
+	m.TrAdd("Tr48x8", 48, 8)
+	m.TrAdd("Tr52x8", 52, 8)
+	m.TrAdd("Tr60x9", 60, 9)
+	m.TrAdd("Tr70x10", 70, 10)
+	m.TrAdd("Tr80x10", 80, 10)
+	m.TrAdd("Tr90x12", 90, 12)
+	m.TrAdd("Tr100x12", 100, 12)
+	// BSP Parallel (G)
+	m.BSPAdd("bsp_1/8", 9.728, 28, 8.8, 0)
+	m.BSPAdd("bsp_1/4", 13.157, 19, 11.8, 0)
+	m.BSPAdd("bsp_3/8", 16.662, 19, 15.25, 0)
+	m.BSPAdd("bsp_1/2", 20.955, 14, 19, 0)
+	m.BSPAdd("bsp_3/4", 26.441, 14, 24.5, 0)
+	m.BSPAdd("bsp_1", 33.249, 11, 30.75, 0)
+	m.BSPAdd("bsp_1-1/4", 41.910, 11, 39.5, 0)
+	m.BSPAdd("bsp_1-1/2", 47.803, 11, 45.25, 0)
+	m.BSPAdd("bsp_2", 59.614, 11, 57, 0)
+	// BSP Tapered (R)
+	m.BSPAdd("bspt_1/8", 9.728, 28, 8.4, 1.0/16.0)
+	m.BSPAdd("bspt_1/4", 13.157, 19, 11.2, 1.0/16.0)
+	m.BSPAdd("bspt_3/8", 16.662, 19, 14.75, 1.0/16.0)
+	m.BSPAdd("bspt_1/2", 20.955, 14, 18.25, 1.0/16.0)
+	m.BSPAdd("bspt_3/4", 26.441, 14, 23.75, 1.0/16.0)
+	m.BSPAdd("bspt_1", 33.249, 11, 30, 1.0/16.0)
+	m.BSPAdd("bspt_1-1/4", 41.910, 11, 38.5, 1.0/16.0)
+	m.BSPAdd("bspt_1-1/2", 47.803, 11, 44.5, 1.0/16.0)
+	m.BSPAdd("bspt_2", 59.614, 11, 56, 1.0/16.0)
+	// NPT
+	m.NPTAdd("npt_1/8", 0.405, 27, 11.0/32.0)
+	m.NPTAdd("npt_1/4", 0.540, 18, 7.0/16.0)
+	m.NPTAdd("npt_3/8", 0.675, 18, 37.0/64.0)
+	m.NPTAdd("npt_1/2", 0.840, 14, 23.0/32.0)
+	m.NPTAdd("npt_3/4", 1.050, 14, 59.0/64.0)
+	m.NPTAdd("npt_1", 1.315, 11.5, 1.0+5.0/32.0)
+	m.NPTAdd("npt_1-1/4", 1.660, 11.5, 1.5)
+	m.NPTAdd("npt_1-1/2", 1.900, 11.5, 1.0+47.0/64.0)
+	m.NPTAdd("npt_2", 2.375, 11.5, 2.0+7.0/32.0)
 	return m
 }
 
 // lookup the parameters for a thread by name
-func ThreadLookup(name string) *ThreadParameters {
+func ThreadLookupErr(name string) (*ThreadParameters, error) {
 	t, ok := thread_db[name]
 	if !ok {
-		panic("thread name not found")
+		return nil, param_error("ThreadLookup", "name", name, "thread name not found")
+	}
+	return t, nil
+}
+
+// lookup the parameters for a thread by name (panic on error)
+func ThreadLookup(name string) *ThreadParameters {
+	t, err := ThreadLookupErr(name)
+	if err != nil {
+		panic(err)
 	}
 	return t
 }
 
 // Hex Head Radius
-func (t *ThreadParameters) Hex_Radius() float64 {
+func (t *ThreadParameters) Hex_RadiusErr() (float64, error) {
 	if t.Hex_Flat2Flat < 0 {
-		panic("no hex head flat to flat distance defined for this thread")
+		return 0, param_error("Hex_Radius", "Hex_Flat2Flat", t.Hex_Flat2Flat, "no hex head flat to flat distance defined for "+t.Name)
+	}
+	return t.Hex_Flat2Flat / (2.0 * math.Cos(DtoR(30))), nil
+}
+
+// Hex Head Radius (panic on error)
+func (t *ThreadParameters) Hex_Radius() float64 {
+	r, err := t.Hex_RadiusErr()
+	if err != nil {
+		panic(err)
 	}
-	return t.Hex_Flat2Flat / (2.0 * math.Cos(DtoR(30)))
+	return r
 }
 
 // Hex Head Height (empirical)
-func (t *ThreadParameters) Hex_Height() float64 {
-	hex_r := t.Hex_Radius()
+func (t *ThreadParameters) Hex_HeightErr() (float64, error) {
+	hex_r, err := t.Hex_RadiusErr()
+	if err != nil {
+		return 0, err
+	}
 	hex_h := 2.0 * hex_r * (5.0 / 12.0)
-	return hex_h
+	return hex_h, nil
+}
+
+// Hex Head Height (panic on error)
+func (t *ThreadParameters) Hex_Height() float64 {
+	h, err := t.Hex_HeightErr()
+	if err != nil {
+		panic(err)
+	}
+	return h
 }
 
 //-----------------------------------------------------------------------------
@@ -235,6 +418,67 @@ func ISOThread(radius, pitch float64, mode string) SDF2 {
 	return Polygon2D(iso.Vertices())
 }
 
+// Return the 2d profile for an ISO trapezoidal (Tr) thread.
+// ISO 2904, 30 degree included angle.
+// radius = radius of thread
+// pitch = thread to thread distance
+func TrapezoidalThread(radius, pitch float64) SDF2 {
+
+	// crest clearance
+	ac := 1.0
+	if pitch <= 1.5 {
+		ac = 0.15
+	} else if pitch <= 5 {
+		ac = 0.25
+	} else if pitch <= 12 {
+		ac = 0.5
+	}
+	h3 := 0.5*pitch + ac
+	t := math.Tan(DtoR(15.0))
+	// half widths of the thread at the crest and root
+	x_crest := 0.25*pitch - 0.25*pitch*t
+	x_root := 0.25*pitch + (h3-0.25*pitch)*t
+
+	tr := NewPolygon()
+	tr.Add(pitch, 0)
+	tr.Add(pitch, radius-h3)
+	tr.Add(x_root, radius-h3)
+	tr.Add(x_crest, radius)
+	tr.Add(-x_crest, radius)
+	tr.Add(-x_root, radius-h3)
+	tr.Add(-pitch, radius-h3)
+	tr.Add(-pitch, 0)
+
+	//tr.Render("tr.dxf")
+	return Polygon2D(tr.Vertices())
+}
+
+// Return the 2d profile for a Whitworth (BSW, BSP) thread.
+// 55 degree included angle with rounded crests and roots.
+// radius = radius of thread
+// pitch = thread to thread distance
+func WhitworthThread(radius, pitch float64) SDF2 {
+
+	H := 0.960491 * pitch
+	h := 0.640327 * pitch
+	r := 0.137329 * pitch
+	// sharp crest and root
+	r_crest := radius + H/6.0
+	r_root := radius - h - H/6.0
+
+	tp := NewPolygon()
+	tp.Add(pitch, 0)
+	tp.Add(pitch, r_crest)
+	tp.Add(pitch/2.0, r_root).Smooth(r, 5)
+	tp.Add(0, r_crest).Smooth(r, 5)
+	tp.Add(-pitch/2.0, r_root).Smooth(r, 5)
+	tp.Add(-pitch, r_crest)
+	tp.Add(-pitch, 0)
+
+	//tp.Render("whitworth.dxf")
+	return Polygon2D(tp.Vertices())
+}
+
 // Return the 2d profile for an ANSI 45/7 buttress thread.
 // https://en.wikipedia.org/wiki/Buttress_thread
 // AMSE B1.9-1973
@@ -298,6 +542,8 @@ type ScrewSDF3 struct {
 	lead   float64 // distance per turn (starts * pitch)
 	length float64 // total length of screw
 	starts int     // number of thread starts
+	taper  float64 // radius change per unit length
+	k      float64 // distance correction factor
 	bb     Box3    // bounding box
 }
 
@@ -307,16 +553,30 @@ func Screw3D(
 	length float64, // length of screw
 	pitch float64, // thread to thread distance
 	starts int, // number of thread starts (< 0 for left hand threads)
+) SDF3 {
+	return TaperedScrew3D(thread, length, 0, pitch, starts)
+}
+
+// Return a tapered screw SDF3.
+// The thread profile is at z = 0 and the radius grows along +z.
+func TaperedScrew3D(
+	thread SDF2, // 2D thread profile
+	length float64, // length of screw
+	taper float64, // diameter change per unit length (E.g. 1/16 for NPT)
+	pitch float64, // thread to thread distance
+	starts int, // number of thread starts (< 0 for left hand threads)
 ) SDF3 {
 	s := ScrewSDF3{}
 	s.thread = thread
 	s.pitch = pitch
 	s.length = length / 2
 	s.lead = -pitch * float64(starts)
+	s.taper = taper / 2
+	s.k = math.Sqrt(1 + s.taper*s.taper)
 	// Work out the bounding box.
 	// The max-y axis of the sdf2 bounding box is the radius of the thread.
 	bb := s.thread.BoundingBox()
-	r := bb.Max.Y
+	r := bb.Max.Y + Abs(s.taper)*s.length
 	s.bb = Box3{V3{-r, -r, -s.length}, V3{r, r, s.length}}
 	return &s
 }
@@ -325,14 +585,14 @@ func (s *ScrewSDF3) Evaluate(p V3) float64 {
 	// map the 3d point back to the xy space of the profile
 	p0 := V2{}
 	// the distance from the 3d z-axis maps to the 2d y-axis
-	p0.Y = math.Sqrt(p.X*p.X + p.Y*p.Y)
+	p0.Y = math.Sqrt(p.X*p.X+p.Y*p.Y) - s.taper*p.Z
 	// the x/y angle and the z-height map to the 2d x-axis
 	// ie: the position along thread pitch
 	theta := math.Atan2(p.Y, p.X)
 	z := p.Z + s.lead*theta/TAU
 	p0.X = SawTooth(z, s.pitch)
 	// get the thread profile distance
-	d0 := s.thread.Evaluate(p0)
+	d0 := s.thread.Evaluate(p0) / s.k
 	// create a region for the screw length
 	d1 := Abs(p.Z) - s.length
 	// return the intersection
@@ -10,6 +10,7 @@ package sdf
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
@@ -247,12 +248,18 @@ type PolySDF2 struct {
 	bb     Box2      // bounding box
 }
 
-func Polygon2D(vertex []V2) SDF2 {
+// NewPolygon2D returns a polygon from a list of vertices.
+func NewPolygon2D(vertex []V2) (SDF2, error) {
 	s := PolySDF2{}
 
 	n := len(vertex)
 	if n < 3 {
-		return nil
+		return nil, param_error("Polygon2D", "len(vertex)", n, "a polygon needs at least 3 vertices")
+	}
+	for i, v := range vertex {
+		if math.IsNaN(v.X) || math.IsNaN(v.Y) || math.IsInf(v.X, 0) || math.IsInf(v.Y, 0) {
+			return nil, param_error("Polygon2D", fmt.Sprintf("vertex[%d]", i), v, "vertex is not finite")
+		}
 	}
 
 	// Close the loop (if necessary)
@@ -278,7 +285,20 @@ func Polygon2D(vertex []V2) SDF2 {
 	}
 
 	s.bb = Box2{vmin, vmax}
-	return &s
+	return &s, nil
+}
+
+// Polygon2D returns a polygon from a list of vertices (panic on error).
+// It returns nil for less than 3 vertices, Union2D/Difference2D ignore a nil SDF2.
+func Polygon2D(vertex []V2) SDF2 {
+	if len(vertex) < 3 {
+		return nil
+	}
+	s, err := NewPolygon2D(vertex)
+	if err != nil {
+		panic(err)
+	}
+	return s
 }
 
 func (s *PolySDF2) Evaluate(p V2) float64 {
@@ -764,6 +784,45 @@ func (s *DifferenceSDF2) BoundingBox() Box2 {
 
 //-----------------------------------------------------------------------------
 
+// Intersection of SDF2s
+type IntersectionSDF2 struct {
+	s0  SDF2
+	s1  SDF2
+	max MaxFunc
+	bb  Box2
+}
+
+// Return the intersection of two SDF2 objects.
+func Intersect2D(s0, s1 SDF2) SDF2 {
+	if s0 == nil || s1 == nil {
+		return nil
+	}
+	s := IntersectionSDF2{}
+	s.s0 = s0
+	s.s1 = s1
+	s.max = Max
+	// TODO fix bounding box
+	s.bb = s0.BoundingBox()
+	return &s
+}
+
+// Return the minimum distance to the object.
+func (s *IntersectionSDF2) Evaluate(p V2) float64 {
+	return s.max(s.s0.Evaluate(p), s.s1.Evaluate(p))
+}
+
+// Set the maximum function to control blending.
+func (s *IntersectionSDF2) SetMax(max MaxFunc) {
+	s.max = max
+}
+
+// Return the bounding box.
+func (s *IntersectionSDF2) BoundingBox() Box2 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+
 // Generate a set of internal mesh points for an SDF2
 func GenerateMesh2D(s SDF2, grid V2i) (V2Set, error) {
 
@@ -59,27 +59,64 @@ func sdf_box3d(p, s V3) float64 {
 
 // Solid of Revolution, SDF2 to SDF3
 type SorSDF3 struct {
-	sdf   SDF2
-	theta float64 // angle for partial revolutions
-	norm  V2      // pre-calculated normal to theta line
-	bb    Box3
+	sdf     SDF2
+	theta   float64 // angle for partial revolutions
+	pitch   float64 // axial offset per revolution (spirals)
+	matrix  M44     // world to local (axis and start angle)
+	inverse M44     // local to world
+	k       float64 // distance correction factor
+	bb      Box3
 }
 
 // Return an SDF3 for a solid of revolution.
 func RevolveTheta3D(sdf SDF2, theta float64) SDF3 {
+	return RevolveSweep3D(sdf, &RevolveParms{Theta: theta})
+}
+
+// Return an SDF3 for a solid of revolution.
+func Revolve3D(sdf SDF2) SDF3 {
+	return RevolveTheta3D(sdf, 0)
+}
+
+type RevolveParms struct {
+	Axis  V3      // axis of revolution through the origin (default z-axis)
+	Start float64 // start angle of a partial revolution (radians)
+	Theta float64 // angle of a partial revolution, 0 == full revolution (radians)
+	Pitch float64 // axial offset per revolution, spirals the profile (partial revolutions only)
+}
+
+// RevolveSweep3D returns an SDF3 for a solid of revolution.
+// The SDF2 x-axis is the radial distance from the axis, the y-axis is the
+// distance along the axis. Partial revolutions start at the local x-axis
+// (see axis_frame) rotated by the start angle. With a non-zero pitch the
+// profile moves along the axis as it revolves and the angle of revolution
+// may be more than one turn.
+func RevolveSweep3D(sdf SDF2, k *RevolveParms) SDF3 {
 	s := SorSDF3{}
 	s.sdf = sdf
-	// normalize theta
-	s.theta = math.Mod(Abs(theta), TAU)
-	sin := math.Sin(s.theta)
-	cos := math.Cos(s.theta)
-	// pre-calculate the normal to the theta line
-	s.norm = V2{-sin, cos}
+	s.pitch = k.Pitch
+	if s.pitch == 0 {
+		// normalize theta
+		s.theta = math.Mod(Abs(k.Theta), TAU)
+	} else {
+		if k.Theta == 0 {
+			panic("spiral revolutions need a non-zero theta")
+		}
+		s.theta = Abs(k.Theta)
+	}
+	axis := k.Axis
+	if axis.Length() == 0 {
+		axis = V3{0, 0, 1}
+	}
+	s.matrix = RotateZ(-k.Start).Mul(axis_frame(axis))
+	s.inverse = s.matrix.Inverse()
 	// work out the bounding box
 	var vset V2Set
-	if s.theta == 0 {
+	if s.theta == 0 || s.theta >= TAU {
 		vset = []V2{{1, 1}, {-1, -1}}
 	} else {
+		sin := math.Sin(s.theta)
+		cos := math.Cos(s.theta)
 		vset = []V2{{0, 0}, {1, 0}, {cos, sin}}
 		if s.theta > 0.5*PI {
 			vset = append(vset, V2{0, 1})
@@ -95,31 +132,79 @@ func RevolveTheta3D(sdf SDF2, theta float64) SDF3 {
 	l := Max(Abs(bb.Min.X), Abs(bb.Max.X))
 	vmin := vset.Min().MulScalar(l)
 	vmax := vset.Max().MulScalar(l)
-	s.bb = Box3{V3{vmin.X, vmin.Y, bb.Min.Y}, V3{vmax.X, vmax.Y, bb.Max.Y}}
+	rise := s.pitch * s.theta / TAU
+	bb3 := Box3{V3{vmin.X, vmin.Y, bb.Min.Y + Min(0, rise)}, V3{vmax.X, vmax.Y, bb.Max.Y + Max(0, rise)}}
+	s.bb = s.inverse.MulBox(bb3)
+	// Spirals shear the profile by pitch/(TAU * r) as it revolves.
+	// This is unbounded at the axis, use the middle of the profile.
+	s.k = 1
+	if s.pitch != 0 {
+		if bb.Max.X <= 0 {
+			panic("spiral profiles need x > 0")
+		}
+		r := Max(bb.Min.X, 0.5*bb.Max.X)
+		t := Abs(s.pitch) / (TAU * r)
+		s.k = 0.5 * (t + math.Sqrt(t*t+4))
+	}
 	return &s
 }
 
-// Return an SDF3 for a solid of revolution.
-func Revolve3D(sdf SDF2) SDF3 {
-	return RevolveTheta3D(sdf, 0)
+// halfplane_distance returns the distance from a point (x,y) to the half plane
+// through the z-axis at angle theta.
+func halfplane_distance(p V2, theta float64) float64 {
+	n := V2{math.Cos(theta), math.Sin(theta)}
+	if p.Dot(n) < 0 {
+		// closest to the z-axis
+		return p.Length()
+	}
+	return Abs(p.Cross(n))
+}
+
+// face_distance returns the distance to the end face of a partial revolution.
+// The end face is the profile located in the half plane at angle theta.
+func (s *SorSDF3) face_distance(p V3, theta, z float64) float64 {
+	n := V2{math.Cos(theta), math.Sin(theta)}
+	p2 := V2{p.X, p.Y}
+	// distance within the plane of the face
+	d := s.sdf.Evaluate(V2{p2.Dot(n), p.Z - z})
+	// distance to the plane of the face
+	h := p2.Cross(n)
+	if d <= 0 {
+		return Abs(h)
+	}
+	return math.Sqrt(d*d + h*h)
 }
 
 // Return the minimum distance to a solid of revolution.
 func (s *SorSDF3) Evaluate(p V3) float64 {
+	p = s.matrix.MulPosition(p)
 	x := math.Sqrt(p.X*p.X + p.Y*p.Y)
-	a := s.sdf.Evaluate(V2{x, p.Z})
-	b := a
-	if s.theta != 0 {
-		// combine two vertical planes to give an intersection wedge
-		d := s.norm.Dot(V2{p.X, p.Y})
-		if s.theta < PI {
-			b = Max(-p.Y, d) // intersect
-		} else {
-			b = Min(-p.Y, d) // union
-		}
+	if s.theta == 0 {
+		// full revolution
+		return s.sdf.Evaluate(V2{x, p.Z})
 	}
-	// return the intersection
-	return Max(a, b)
+	phi := math.Atan2(p.Y, p.X)
+	if phi < 0 {
+		phi += TAU
+	}
+	// distance to the revolved profile (all turns covering this angle)
+	c := s.pitch / TAU
+	d := math.MaxFloat64
+	for psi := phi; psi <= s.theta; psi += TAU {
+		d = Min(d, s.sdf.Evaluate(V2{x, p.Z - c*psi}))
+	}
+	if d == math.MaxFloat64 {
+		// outside the angular range: the closest point is on an end face
+		d = Min(s.face_distance(p, 0, 0), s.face_distance(p, s.theta, c*s.theta))
+	} else if d < 0 {
+		// inside: limit the distance by the end face half planes
+		p2 := V2{p.X, p.Y}
+		d = Max(d, -Min(halfplane_distance(p2, 0), halfplane_distance(p2, s.theta)))
+	} else if s.pitch != 0 {
+		// outside: the end faces of a spiral may be closer
+		d = Min(d, Min(s.face_distance(p, 0, 0), s.face_distance(p, s.theta, c*s.theta)))
+	}
+	return d / s.k
 }
 
 // Return the bounding box for a solid of revolution.
@@ -207,6 +292,29 @@ func (s *ExtrudeSDF3) BoundingBox() Box3 {
 	return s.bb
 }
 
+//-----------------------------------------------------------------------------
+
+// rounded_distance combines the distance to a 2d profile (a) with the
+// distance to the extrusion z-range (b). The result is exact outside.
+func rounded_distance(a, b float64) float64 {
+	if b > 0 {
+		// outside the object Z extent
+		if a < 0 {
+			// inside the boundary
+			return b
+		}
+		// outside the boundary
+		return math.Sqrt((a * a) + (b * b))
+	}
+	// within the object Z extent
+	if a < 0 {
+		// inside the boundary
+		return Max(a, b)
+	}
+	// outside the boundary
+	return a
+}
+
 //-----------------------------------------------------------------------------
 // Linear extrude an SDF2 with rounded edges.
 // Note: The height of the extrusion is adjusted for the rounding.
@@ -243,27 +351,7 @@ func (s *ExtrudeRoundedSDF3) Evaluate(p V3) float64 {
 	// sdf for the projected 2d surface
 	a := s.sdf.Evaluate(V2{p.X, p.Y})
 	b := Abs(p.Z) - s.height
-	var d float64
-	if b > 0 {
-		// outside the object Z extent
-		if a < 0 {
-			// inside the boundary
-			d = b
-		} else {
-			// outside the boundary
-			d = math.Sqrt((a * a) + (b * b))
-		}
-	} else {
-		// within the object Z extent
-		if a < 0 {
-			// inside the boundary
-			d = Max(a, b)
-		} else {
-			// outside the boundary
-			d = a
-		}
-	}
-	return d - s.round
+	return rounded_distance(a, b) - s.round
 }
 
 func (s *ExtrudeRoundedSDF3) BoundingBox() Box3 {
@@ -308,30 +396,124 @@ func (s *LoftSDF3) Evaluate(p V3) float64 {
 	a := Mix(a0, a1, k)
 
 	b := Abs(p.Z) - s.height
-	var d float64
-	if b > 0 {
-		// outside the object Z extent
-		if a < 0 {
-			// inside the boundary
-			d = b
-		} else {
-			// outside the boundary
-			d = math.Sqrt((a * a) + (b * b))
+	return rounded_distance(a, b) - s.round
+}
+
+func (s *LoftSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+// Multi-section Loft (with rounded edges)
+// Blend between N sdfs located at increasing z heights.
+// Note: The z extent of the loft is adjusted for the rounding.
+// The underlying SDF2 shapes are not modified.
+
+type MultiLoftSDF3 struct {
+	sdf    []SDF2
+	height []float64
+	smooth bool
+	round  float64
+	bb     Box3
+}
+
+// MultiLoft3D lofts through SDF2 profiles located at increasing z heights.
+// With smooth == false the profiles are blended linearly. With smooth == true
+// a Catmull-Rom style cubic blend is used. The cubic is limited to avoid
+// overshoot, so the loft never extends beyond the neighbouring profiles.
+func MultiLoft3D(
+	sdf []SDF2, // profiles
+	height []float64, // z height of each profile
+	smooth bool, // smooth (cubic) or linear blending
+	round float64, // rounding radius for the top/bottom edges
+) SDF3 {
+	if len(sdf) < 2 {
+		panic("need at least 2 profiles")
+	}
+	if len(sdf) != len(height) {
+		panic("len(sdf) != len(height)")
+	}
+	for i := 1; i < len(height); i++ {
+		if height[i] <= height[i-1] {
+			panic("profile heights must be increasing")
 		}
-	} else {
-		// within the object Z extent
-		if a < 0 {
-			// inside the boundary
-			d = Max(a, b)
-		} else {
-			// outside the boundary
-			d = a
+	}
+	n := len(height)
+	if height[n-1]-height[0] < 2*round {
+		panic("height < 2 * round")
+	}
+	s := MultiLoftSDF3{
+		sdf:    sdf,
+		height: height,
+		smooth: smooth,
+		round:  round,
+	}
+	// work out the bounding box
+	bb := sdf[0].BoundingBox()
+	for _, x := range sdf[1:] {
+		bb = bb.Extend(x.BoundingBox())
+	}
+	s.bb = Box3{V3{bb.Min.X - round, bb.Min.Y - round, height[0]}, V3{bb.Max.X + round, bb.Max.Y + round, height[n-1]}}
+	return &s
+}
+
+// loft_slope returns the limited Catmull-Rom slope at a profile.
+// d0, d1 are the secant slopes before and after the profile.
+func loft_slope(d0, d1 float64) float64 {
+	if d0*d1 <= 0 {
+		// local extremum - flatten it to avoid overshoot
+		return 0
+	}
+	m := 0.5 * (d0 + d1)
+	// limit the slope to keep the cubic monotonic
+	if Abs(m) > 3*Min(Abs(d0), Abs(d1)) {
+		m = 3 * Sign(m) * Min(Abs(d0), Abs(d1))
+	}
+	return m
+}
+
+// Evaluate returns the minimum distance to a multi-section loft.
+func (s *MultiLoftSDF3) Evaluate(p V3) float64 {
+	n := len(s.height)
+	// find the interval for this z height
+	z := Clamp(p.Z, s.height[0], s.height[n-1])
+	i := 0
+	for i < n-2 && z > s.height[i+1] {
+		i++
+	}
+	h := s.height[i+1] - s.height[i]
+	t := (z - s.height[i]) / h
+	// mix the 2D SDFs
+	q := V2{p.X, p.Y}
+	a0 := s.sdf[i].Evaluate(q)
+	a1 := s.sdf[i+1].Evaluate(q)
+	var a float64
+	if s.smooth {
+		// secant slopes for the interval and its neighbours
+		d := (a1 - a0) / h
+		d0, d1 := d, d
+		if i > 0 {
+			d0 = (a0 - s.sdf[i-1].Evaluate(q)) / (s.height[i] - s.height[i-1])
+		}
+		if i < n-2 {
+			d1 = (s.sdf[i+2].Evaluate(q) - a1) / (s.height[i+2] - s.height[i+1])
 		}
+		m0 := h * loft_slope(d0, d)
+		m1 := h * loft_slope(d, d1)
+		// cubic hermite
+		t2 := t * t
+		t3 := t2 * t
+		a = (2*t3-3*t2+1)*a0 + (t3-2*t2+t)*m0 + (-2*t3+3*t2)*a1 + (t3-t2)*m1
+	} else {
+		a = Mix(a0, a1, t)
 	}
-	return d - s.round
+	// distance to the z extent
+	b := Max(s.height[0]+s.round-p.Z, p.Z-s.height[n-1]+s.round)
+	return rounded_distance(a, b) - s.round
 }
 
-func (s *LoftSDF3) BoundingBox() Box3 {
+// BoundingBox returns the bounding box for a multi-section loft.
+func (s *MultiLoftSDF3) BoundingBox() Box3 {
 	return s.bb
 }
 
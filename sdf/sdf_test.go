@@ -722,3 +722,1057 @@ func Test_Box2_Distances(t *testing.T) {
 }
 
 //-----------------------------------------------------------------------------
+
+func Test_Deform3D(t *testing.T) {
+	// twisting a cylinder about its own axis doesn't change its shape
+	s0 := Cylinder3D(10, 2, 0)
+	s1 := Twist3D(s0, V3{0, 0, 1}, DtoR(30))
+	bb := s0.BoundingBox().ScaleAboutCenter(1.5)
+	for _, p := range bb.RandomSet(100) {
+		if Sign(s0.Evaluate(p)) != Sign(s1.Evaluate(p)) {
+			t.Error("FAIL")
+		}
+	}
+	// bend a bar along the x-axis through 90 degrees
+	r := 10.0
+	bar := Transform3D(Box3D(V3{0.5 * PI * r, 1, 1}, 0), Translate3d(V3{0.25 * PI * r, 0, 0}))
+	s2 := Bend3D(bar, V3{0, 0, 1}, r)
+	p := V3{r * SQRT_HALF, r * (1 - SQRT_HALF), 0}
+	if s2.Evaluate(p) >= 0 {
+		t.Error("FAIL")
+	}
+	if !s2.BoundingBox().Equals(Box3{V3{0, -0.5, -0.5}, V3{r + 0.5, r, 0.5}}, 1e-6) {
+		t.Logf("bounding box %v\n", s2.BoundingBox())
+		t.Error("FAIL")
+	}
+	// taper a box and check the ends
+	s3 := Taper3D(Box3D(V3{2, 2, 10}, 0), V3{0, 0, 1}, 1, 0.5)
+	if s3.Evaluate(V3{0.9, 0, -4.9}) >= 0 || s3.Evaluate(V3{0.9, 0, 4.9}) <= 0 {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Sweep3D(t *testing.T) {
+	// straight path along the z-axis
+	s := Sweep3D(Circle2D(1), V3Set{{0, 0, 0}, {0, 0, 10}})
+	if Abs(s.Evaluate(V3{2, 0, 5})-1) > TOLERANCE || Abs(s.Evaluate(V3{0, 0, 5})+1) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	if Abs(s.Evaluate(V3{0, 0, 12})-2) > TOLERANCE || Abs(s.Evaluate(V3{0, 0.5, -1})-1) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	// bent path with a 90 degree corner
+	s = Sweep3D(Circle2D(1), V3Set{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}})
+	if Abs(s.Evaluate(V3{5, 2, 0})-1) > TOLERANCE || Abs(s.Evaluate(V3{11.5, 5, 0})-0.5) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	if Abs(s.Evaluate(V3{10, 0, 0})+1) > TOLERANCE || Abs(s.Evaluate(V3{10, 10, 3})-2) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	bb := s.BoundingBox()
+	if bb.Min.X > -1 || bb.Max.X < 11 || bb.Max.Y < 11 {
+		t.Error("FAIL")
+	}
+	// twist by 90 degrees and scale by 2 along the path
+	s = ScaleTwistSweep3D(Box2D(V2{2, 1}, 0), V3Set{{0, 0, 0}, {0, 0, 10}}, DtoR(90), 2)
+	seg := s.(*SweepSDF3).segment[0]
+	// the start profile is on the frame u/v axes
+	if s.Evaluate(seg.u.MulScalar(0.9).Add(V3{0, 0, 0.1})) >= 0 || s.Evaluate(seg.v.MulScalar(0.9).Add(V3{0, 0, 0.1})) <= 0 {
+		t.Error("FAIL")
+	}
+	// the end profile is rotated and scaled
+	end := V3{0, 0, 10}
+	d0 := s.Evaluate(end.Add(seg.v.MulScalar(2.5)))
+	d1 := s.Evaluate(end.Add(seg.u.MulScalar(2.5)))
+	if d0 <= 0 || d0 > 0.5+TOLERANCE || d1 <= d0 || s.Evaluate(end.Add(seg.v.MulScalar(1.9)).Sub(V3{0, 0, 0.1})) >= 0 {
+		t.Error("FAIL")
+	}
+	// an empty path is rejected
+	defer func() {
+		if r := recover(); r != "sweep path needs at least 2 distinct points" {
+			t.Error("FAIL")
+		}
+	}()
+	Sweep3D(Circle2D(1), V3Set{})
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Helix3D(t *testing.T) {
+	// 3 turns, 15 high, centered on the origin
+	s := Helix3D(10, 5, 3, Circle2D(1))
+	// a point on the wire center line, 1.25 turns from the start
+	p := V3{0, 10, 5*1.25 - 7.5}
+	if Abs(s.Evaluate(p)+1) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	// half a pitch away is between the turns
+	if s.Evaluate(p.Add(V3{0, 0, 2.5})) <= 0 {
+		t.Error("FAIL")
+	}
+	// the wire is inclined by the helix angle
+	h := 7.5 + math.Hypot(10, 5/TAU)/10
+	if !s.BoundingBox().Equals(Box3{V3{-11, -11, -h}, V3{11, 11, h}}, TOLERANCE) {
+		t.Error("FAIL")
+	}
+	// left hand
+	s = Helix3D(10, 5, -3, Circle2D(1))
+	if Abs(s.Evaluate(V3{0, -10, p.Z})+1) > TOLERANCE || s.Evaluate(p) <= 0 {
+		t.Error("FAIL")
+	}
+	// ground ends are cut flat at the end turns
+	s = Spring3D(&SpringParms{Radius: 10, Pitch: 5, Turns: 5, Wire: Circle2D(1), Ends: "ground"})
+	if !s.BoundingBox().Equals(Box3{V3{-11, -11, -7.5}, V3{11, 11, 7.5}}, TOLERANCE) {
+		t.Error("FAIL")
+	}
+	if Abs(s.Evaluate(V3{10, 0, 7.5})) > TOLERANCE || Abs(s.Evaluate(V3{0, 10, -7.5})) > TOLERANCE {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_MultiLoft3D(t *testing.T) {
+	profiles := []SDF2{Circle2D(2), Circle2D(4), Circle2D(3)}
+	height := []float64{0, 10, 20}
+	for _, smooth := range []bool{false, true} {
+		s := MultiLoft3D(profiles, height, smooth, 0)
+		// the loft passes through each profile
+		for i, r := range []float64{2, 4, 3} {
+			z := height[i]
+			if Abs(s.Evaluate(V3{r, 0, z})) > TOLERANCE || s.Evaluate(V3{0, r + 1, z}) <= 0 {
+				t.Error("FAIL")
+			}
+		}
+		if Abs(s.Evaluate(V3{0, 3, 10})+1) > TOLERANCE {
+			t.Error("FAIL")
+		}
+	}
+	// linear blending between profiles
+	s := MultiLoft3D(profiles, height, false, 0)
+	if Abs(s.Evaluate(V3{3, 0, 5})) > TOLERANCE || Abs(s.Evaluate(V3{0, 3.5, 15})) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	// smooth blending is fuller, but doesn't overshoot the profiles
+	s = MultiLoft3D(profiles, height, true, 0)
+	if d := s.Evaluate(V3{3, 0, 5}); d >= 0 || d < -1 {
+		t.Error("FAIL")
+	}
+	if s.Evaluate(V3{4.01, 0, 12}) <= 0 {
+		t.Error("FAIL")
+	}
+	if !s.BoundingBox().Equals(Box3{V3{-4, -4, 0}, V3{4, 4, 20}}, TOLERANCE) {
+		t.Error("FAIL")
+	}
+	// at least 2 profiles are needed
+	defer func() {
+		if r := recover(); r != "need at least 2 profiles" {
+			t.Error("FAIL")
+		}
+	}()
+	MultiLoft3D(profiles[:1], height[:1], false, 0)
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_RevolveSweep3D(t *testing.T) {
+	profile := Transform2D(Box2D(V2{2, 2}, 0), Translate2d(V2{5, 0}))
+	// full revolution
+	s := Revolve3D(profile)
+	if Abs(s.Evaluate(V3{0, -7, 0})-1) > TOLERANCE || Abs(s.Evaluate(V3{-5, 0, 0})+1) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	// quarter revolution from the x-axis to the y-axis
+	s = RevolveSweep3D(profile, &RevolveParms{Theta: 0.5 * PI})
+	p := PolarToXY(7, 0.25*PI)
+	if Abs(s.Evaluate(V3{p.X, p.Y, 0})-1) > TOLERANCE || Abs(s.Evaluate(V3{5, 0.5, 0})+0.5) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	// exact distances to the end faces
+	if Abs(s.Evaluate(V3{5, -2, 0})-2) > TOLERANCE || Abs(s.Evaluate(V3{5, -2, 3})-math.Sqrt(8)) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	if Abs(s.Evaluate(V3{-2, 5, 0.5})-2) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	if !s.BoundingBox().Equals(Box3{V3{0, 0, -1}, V3{6, 6, 1}}, TOLERANCE) {
+		t.Logf("bounding box %v\n", s.BoundingBox())
+		t.Error("FAIL")
+	}
+	// one turn of a spiral, the profile rises 4 per turn
+	s = RevolveSweep3D(profile, &RevolveParms{Theta: TAU, Pitch: 4})
+	if s.Evaluate(V3{-5, 0, 2}) >= 0 || s.Evaluate(V3{-5, 0, 0}) <= 0 || s.Evaluate(V3{0, -5, 3}) >= 0 {
+		t.Error("FAIL")
+	}
+	// a profile straddling the axis has a finite distance correction
+	s = RevolveSweep3D(Box2D(V2{2, 2}, 0), &RevolveParms{Theta: TAU, Pitch: 4})
+	if d := s.Evaluate(V3{3, 0, 0}); d <= 0 || d > 2 || math.IsNaN(d) {
+		t.Error("FAIL")
+	}
+	// spirals can't be evaluated for profiles on the -x side of the axis
+	defer func() {
+		if r := recover(); r != "spiral profiles need x > 0" {
+			t.Error("FAIL")
+		}
+	}()
+	RevolveSweep3D(Transform2D(profile, Translate2d(V2{-10, 0})), &RevolveParms{Theta: TAU, Pitch: 4})
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_InternalGear(t *testing.T) {
+	pa := DtoR(20)
+	s := InternalInvoluteGear(30, 1, pa, 0, 0.25, 1, 3, 5)
+	// the addendum is limited to the base circle
+	tip := 15 * math.Cos(pa)
+	root := 16.25
+	// a tooth space is centered on the x-axis (the root is faceted)
+	if Abs(s.Evaluate(V2{root + 0.1, 0})+0.1) > 0.01 || s.Evaluate(V2{root - 0.1, 0}) <= 0 {
+		t.Error("FAIL")
+	}
+	// tooth tip
+	p := PolarToXY(tip+0.1, PI/30)
+	q := PolarToXY(tip-0.1, PI/30)
+	if s.Evaluate(p) >= 0 || Abs(s.Evaluate(q)-0.1) > 1e-3 {
+		t.Error("FAIL")
+	}
+	// rim
+	if Abs(s.Evaluate(V2{0, root + 3.1})-0.1) > 1e-3 {
+		t.Error("FAIL")
+	}
+	// tooth count pairs
+	if InternalGearCheck(12, 15, 1, pa, 1) == nil {
+		t.Error("FAIL")
+	}
+	if InternalGearCheck(12, 15, 1, pa, 0.25) == nil {
+		t.Error("FAIL")
+	}
+	if err := InternalGearCheck(12, 60, 1, pa, InternalGearAddendum(12, 60, 1, pa)); err != nil {
+		t.Error(err)
+	}
+	if InternalGearCheck(12, 12, 1, pa, 1) == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_PlanetaryGears(t *testing.T) {
+	k := PlanetaryParms{
+		Ratio:            4,
+		NumberPlanets:    3,
+		Module:           1,
+		PressureAngle:    DtoR(20),
+		RimWidth:         3,
+		FaceWidth:        5,
+		CarrierThickness: 2,
+		Facets:           5,
+	}
+	g, err := MakePlanetaryGears(&k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.SunTeeth != 12 || g.PlanetTeeth != 12 || g.RingTeeth != 36 || g.Ratio != 4 {
+		t.Logf("teeth %d %d %d ratio %f\n", g.SunTeeth, g.PlanetTeeth, g.RingTeeth, g.Ratio)
+		t.Error("FAIL")
+	}
+	if len(g.Planets) != 3 || !g.Planet[0].Equals(V2{12, 0}, TOLERANCE) {
+		t.Error("FAIL")
+	}
+	// the gears are solid
+	if g.Sun.Evaluate(V2{0, 0}) >= 0 || g.Planets[0].Evaluate(g.Planet[0]) >= 0 {
+		t.Error("FAIL")
+	}
+	// the ring is always larger than the sun
+	k.Ratio = 2
+	if _, err := MakePlanetaryGears(&k); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_HelicalGear(t *testing.T) {
+	// the tooth advances one pitch (normal module * PI / sin(helix_angle)) along the pitch circle
+	beta := DtoR(30)
+	w := PI / math.Sin(beta)
+	twist := HelicalGearTwist(20, 1, beta, w, false)
+	if Abs(twist-TAU/20) > TOLERANCE {
+		t.Logf("twist %f\n", twist)
+		t.Error("FAIL")
+	}
+	if HelicalGearTwist(20, 1, beta, w, true) != -twist {
+		t.Error("FAIL")
+	}
+	// the halves of a herringbone gear are mirror images
+	s := HerringboneGear3D(20, 1, DtoR(20), beta, 0, 0, 0, 10, false, 5)
+	for _, p := range []V3{{11.3, 0.6, 1}, {11.3, 0.6, 3}, {-9, 4, 4.5}} {
+		if Abs(s.Evaluate(p)-s.Evaluate(V3{p.X, p.Y, -p.Z})) > TOLERANCE {
+			t.Error("FAIL")
+		}
+	}
+	// a flank point on the pitch circle moves z * tan(helix_angle) along the pitch circle
+	m, _ := helical_transverse(1, DtoR(20), beta)
+	r := 10 * m
+	s = HelicalGear3D(20, 1, DtoR(20), beta, 0, 0, 0, 10, false, 20)
+	lh := HelicalGear3D(20, 1, DtoR(20), beta, 0, 0, 0, 10, true, 20)
+	for _, z := range []float64{-3, 0, 2} {
+		a := PI/40 + z*math.Tan(beta)/r
+		p := PolarToXY(r, a)
+		if Abs(s.Evaluate(V3{p.X, p.Y, z})) > 0.01 {
+			t.Logf("z %f: %f\n", z, s.Evaluate(V3{p.X, p.Y, z}))
+			t.Error("FAIL")
+		}
+		// the left hand gear twists the other way
+		q := PolarToXY(r, PI/40-z*math.Tan(beta)/r)
+		if Abs(lh.Evaluate(V3{q.X, q.Y, z})) > 0.01 {
+			t.Error("FAIL")
+		}
+	}
+	// the rack teeth are offset z * tan(helix_angle) along the rack
+	m, pa := helical_transverse(1, DtoR(20), beta)
+	rack := GearRack2D(10, m, pa, 0, 2)
+	// a flank point at mid tooth height (tooth thickness is half the pitch)
+	p := V2{0.25 * PI * m, 2 + 1.125*m}
+	if Abs(rack.Evaluate(p)) > 0.01 {
+		t.Error("FAIL")
+	}
+	s = HelicalRack3D(10, 1, DtoR(20), beta, 0, 2, 10, false)
+	for _, z := range []float64{-3, 0, 2} {
+		d := s.Evaluate(V3{p.X + z*math.Tan(beta), p.Y, z})
+		if Abs(d) > 0.01 {
+			t.Logf("z %f: %f\n", z, d)
+			t.Error("FAIL")
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_BevelGear(t *testing.T) {
+	// the pitch cone angles of a right angle pair add up to 90 degrees
+	d1 := BevelPitchAngle(15, 30, PI/2)
+	d2 := BevelPitchAngle(30, 15, PI/2)
+	if Abs(d1+d2-PI/2) > TOLERANCE || Abs(math.Tan(d1)-0.5) > TOLERANCE {
+		t.Error("FAIL")
+	}
+	k := BevelGearParms{NumberTeeth: 15, Module: 2, PressureAngle: DtoR(20), MatingTeeth: 30, FaceWidth: 8, Facets: 5}
+	s := BevelGear3D(&k)
+	// the pitch cone is inside a tooth centered on the x-axis and outside between the teeth
+	a := 15.0 / math.Sin(d1)
+	apex := a * math.Cos(d1)
+	for _, l := range []float64{a - 1, a - 7} {
+		p := V3{l * math.Sin(d1), 0, apex - l*math.Cos(d1)}
+		if s.Evaluate(p) >= 0 {
+			t.Error("FAIL")
+		}
+		q := RotateZ(PI / 15).MulPosition(p)
+		if s.Evaluate(q) <= 0 {
+			t.Error("FAIL")
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_WormGears(t *testing.T) {
+	k := WormGearParms{
+		Module:        2,
+		PressureAngle: DtoR(20),
+		Starts:        2,
+		WheelTeeth:    30,
+		LeadAngle:     math.Atan(0.25),
+		WormLength:    40,
+		FaceWidth:     12,
+		Facets:        5,
+	}
+	g, err := MakeWormGears(&k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// worm pitch radius = lead / (2 * PI * tan(lead angle))
+	if Abs(g.WormRadius-8) > TOLERANCE || Abs(g.CenterDistance-38) > TOLERANCE || g.Ratio != 15 {
+		t.Logf("worm radius %f center distance %f ratio %f\n", g.WormRadius, g.CenterDistance, g.Ratio)
+		t.Error("FAIL")
+	}
+	// a wheel tooth sits in a worm thread space
+	p := V3{0, g.WheelRadius + 0.5*k.Module, 0}
+	if g.Wheel.Evaluate(p) >= 0 || g.Worm.Evaluate(p) <= 0 {
+		t.Error("FAIL")
+	}
+	k.LeadAngle = DtoR(50)
+	if _, err := MakeWormGears(&k); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Pulley(t *testing.T) {
+	b := BeltLookup("GT2_2mm")
+	if Abs(b.OutsideDiameter(20)-12.224) > 1e-3 {
+		t.Logf("outside diameter %f\n", b.OutsideDiameter(20))
+		t.Error("FAIL")
+	}
+	s := Pulley3D(&PulleyParms{Belt: "GT2_2mm", NumberTeeth: 20, BeltWidth: 7, Bore: 5})
+	r := 0.5 * b.OutsideDiameter(20)
+	// groove centered on the x-axis, land between the grooves
+	if s.Evaluate(V3{r - 0.3, 0, 0}) <= 0 || s.Evaluate(PolarToXY(r-0.1, PI/20).ToV3(0)) >= 0 {
+		t.Error("FAIL")
+	}
+	if s.Evaluate(V3{2, 0, 0}) <= 0 {
+		t.Error("FAIL")
+	}
+	// single flange pulleys have the bore open at both ends
+	for _, flanges := range []string{"top", "bottom"} {
+		s = Pulley3D(&PulleyParms{Belt: "GT2_2mm", NumberTeeth: 20, BeltWidth: 7, Bore: 5, Flanges: flanges, FlangeHeight: 1, FlangeThickness: 2})
+		bb := s.BoundingBox()
+		if Abs(bb.Size().Z-9) > TOLERANCE {
+			t.Error("FAIL")
+		}
+		for _, z := range []float64{bb.Min.Z + 0.1, bb.Max.Z - 0.1} {
+			if s.Evaluate(V3{0, 0, z}) <= 0 || s.Evaluate(V3{2.4, 0, z}) <= 0 {
+				t.Error("FAIL")
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Sprocket(t *testing.T) {
+	c := ChainLookup("ANSI_40")
+	n := 15
+	s := Sprocket2D(c, n)
+	r := 0.5 * c.PitchDiameter(n)
+	// the rollers are clear of the sprocket and the tooth is solid
+	for i := 0; i < n; i++ {
+		theta := TAU * float64(i) / float64(n)
+		if s.Evaluate(PolarToXY(r, theta)) < 0.5*c.RollerDiameter {
+			t.Error("FAIL")
+		}
+		if s.Evaluate(PolarToXY(r, theta+PI/float64(n))) >= 0 {
+			t.Error("FAIL")
+		}
+	}
+	if Abs(r-30.5418) > 1e-3 || !s.BoundingBox().Equals(Box2{V2{-32.254, -32.254}, V2{32.254, 32.254}}, 1e-3) {
+		t.Logf("pitch radius %f bounding box %v\n", r, s.BoundingBox())
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_GearPair(t *testing.T) {
+	// profile shifted pair (KHK gear technical reference example)
+	g, err := NewGearPair(12, 24, 3, DtoR(20), 0.6, 0.36)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Abs(g.CenterDistance-56.4999) > 1e-3 || Abs(RtoD(g.WorkingPressureAngle)-26.0886) > 1e-3 {
+		t.Logf("center distance %f working pressure angle %f\n", g.CenterDistance, RtoD(g.WorkingPressureAngle))
+		t.Error("FAIL")
+	}
+	if Abs(g.OuterRadius[0]-g.TipReduction-22.415) > 0.01 {
+		t.Error("FAIL")
+	}
+	// hand calculated from the reduced outside radii (22.420, 39.700)
+	if Abs(g.ContactRatio-1.2021) > 1e-3 {
+		t.Logf("contact ratio %f\n", g.ContactRatio)
+		t.Error("FAIL")
+	}
+	// the shifted gear is made with the reduced outside radius
+	k := &InvoluteGearParms{
+		NumberTeeth:   12,
+		Module:        3,
+		PressureAngle: DtoR(20),
+		ProfileShift:  0.6,
+		TipReduction:  g.TipReduction,
+	}
+	for _, facets := range []int{0, 10} {
+		k.Facets = facets
+		s := InvoluteGearShifted(k)
+		// the tooth tips touch the outside circle
+		tip := false
+		for i := 0; i < 3600; i++ {
+			a := TAU * float64(i) / 3600
+			if s.Evaluate(PolarToXY(22.415+0.02, a)) <= 0 {
+				t.Error("FAIL")
+				break
+			}
+			if s.Evaluate(PolarToXY(22.415-0.02, a)) < 0 {
+				tip = true
+			}
+		}
+		if !tip {
+			t.Error("FAIL")
+		}
+	}
+	// a shifted tooth is thicker at the pitch circle by 2 * x * module * tan(pressure angle)
+	k = &InvoluteGearParms{NumberTeeth: 20, Module: 1, PressureAngle: DtoR(20), Facets: 50}
+	s0 := InvoluteGearToothShifted(k)
+	k.ProfileShift = 0.5
+	s1 := InvoluteGearToothShifted(k)
+	p := PolarToXY(10, PI/40)
+	if Abs(s0.Evaluate(p)) > 0.01 || Abs(s1.Evaluate(p)+0.5*math.Sin(DtoR(20))) > 0.01 {
+		t.Logf("unshifted %f shifted %f\n", s0.Evaluate(p), s1.Evaluate(p))
+		t.Error("FAIL")
+	}
+	// standard pair
+	g, _ = NewGearPair(20, 20, 1, DtoR(20), 0, 0)
+	if Abs(g.CenterDistance-20) > 1e-6 || Abs(g.ContactRatio-1.557) > 1e-3 {
+		t.Error("FAIL")
+	}
+	// a 10 tooth pinion is undercut without profile shift
+	g, _ = NewGearPair(10, 30, 1, DtoR(20), 0, 0)
+	if !g.Undercut[0] || g.Undercut[1] {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_InvoluteGear2D(t *testing.T) {
+	// compare the analytic gear with a finely faceted polygon gear
+	for _, n := range []int{8, 20, 45} {
+		k := &InvoluteGearParms{
+			NumberTeeth:   n,
+			Module:        1,
+			PressureAngle: DtoR(20),
+			ProfileShift:  0.2,
+			Backlash:      0.05,
+			Clearance:     0.1,
+			RingWidth:     2,
+		}
+		s0 := InvoluteGearShifted(k)
+		k.Facets = 200
+		s1 := InvoluteGearShifted(k)
+		bb := s0.BoundingBox().ScaleAboutCenter(1.2)
+		for _, p := range bb.RandomSet(1000) {
+			d0 := s0.Evaluate(p)
+			d1 := s1.Evaluate(p)
+			// the polygon gear is only exact outside
+			if d1 > 0 && Abs(d0-d1) > 0.01 {
+				t.Logf("%v: analytic %f polygon %f\n", p, d0, d1)
+				t.Error("FAIL")
+			}
+			if Abs(d1) > 0.01 && Sign(d0) != Sign(d1) {
+				t.Error("FAIL")
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_ThreadDatabase(t *testing.T) {
+	k := ThreadLookup("M100x4")
+	if k.Radius != 50 || k.Pitch != 4 || k.TapDrill != 96 || k.Form != "iso" {
+		t.Error("FAIL")
+	}
+	if Abs(k.MinorRadius-47.835) > 0.001 {
+		t.Error("FAIL")
+	}
+	k = ThreadLookup("Tr20x4")
+	if k.MinorRadius != 8 || k.TapDrill != 16 || k.Taper != 0 {
+		t.Error("FAIL")
+	}
+	k = ThreadLookup("npt_1/2")
+	if k.Units != "inch" || k.Taper != 1.0/16.0 || Abs(k.Pitch-1.0/14.0) > 1e-9 {
+		t.Error("FAIL")
+	}
+	k = ThreadLookup("bspt_1/2")
+	if k.Form != "whitworth" || k.Taper != 1.0/16.0 || ThreadLookup("bsp_1/2").Taper != 0 {
+		t.Error("FAIL")
+	}
+	// a tapered thread grows along +z
+	r := 10.0
+	p := 1.5
+	s := TaperedScrew3D(ISOThread(r, p, "external"), 20, 1.0/16.0, p, 1)
+	for _, z := range []float64{-8, 0, 8} {
+		// the thread crest is on the x-axis at multiples of the pitch
+		zc := p * math.Round(z/p)
+		x := r + zc/32.0
+		if Abs(s.Evaluate(V3{x + 0.1, 0, zc})-0.1) > 0.01 {
+			t.Error("FAIL")
+		}
+	}
+	for _, name := range []string{"Tr12x3", "bsp_1"} {
+		k = ThreadLookup(name)
+		var thread SDF2
+		if k.Form == "tr" {
+			thread = TrapezoidalThread(k.Radius, k.Pitch)
+		} else {
+			thread = WhitworthThread(k.Radius, k.Pitch)
+		}
+		// the crest is at the major radius
+		if Abs(thread.Evaluate(V2{0, k.Radius + 0.1})-0.1) > 0.01 {
+			t.Error("FAIL")
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_ParamErrors(t *testing.T) {
+	_, err := ThreadLookupErr("M7x0.8")
+	if pe, ok := err.(*ParamError); !ok || pe.Param != "name" || pe.Value != "M7x0.8" {
+		t.Error("FAIL")
+	}
+	k, err := ThreadLookupErr("M6x1")
+	if err != nil || k.Name != "M6x1" {
+		t.Error("FAIL")
+	}
+	if _, err := ThreadLookup("Tr8x2").Hex_HeightErr(); err == nil {
+		t.Error("FAIL")
+	}
+	if _, err := NewPolygon2D([]V2{{0, 0}, {1, 0}}); err == nil {
+		t.Error("FAIL")
+	}
+	if Polygon2D([]V2{{0, 0}, {1, 0}}) != nil {
+		t.Error("FAIL")
+	}
+	_, err = NewPanelBox3D(&PanelBoxParms{Size: V3{50, 40, 60}, Wall: 2.5, Panel: -3})
+	if pe, ok := err.(*ParamError); !ok || pe.Param != "Panel" {
+		t.Error("FAIL")
+	}
+	// the panicking forms panic with the same error
+	defer func() {
+		if _, ok := recover().(*ParamError); !ok {
+			t.Error("FAIL")
+		}
+	}()
+	Washer3D(1, 3, 2)
+	t.Error("FAIL")
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Bolt(t *testing.T) {
+	// a nut on the thread of a bolt must not overlap it
+	tol := 0.2
+	for _, name := range []string{"M6x1", "Tr12x3"} {
+		for _, style := range []string{"hex", "socket", "button", "countersunk"} {
+			bolt, err := NewBolt3D(&BoltParms{name, style, tol, 30, 10})
+			if name == "Tr12x3" && style == "hex" {
+				// no hex dimensions for trapezoidal threads
+				if err == nil {
+					t.Error("FAIL")
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the head is below z = 0
+			if Abs(bolt.BoundingBox().Max.Z-30) > 0.01 || bolt.BoundingBox().Min.Z >= 0 {
+				t.Error("FAIL")
+			}
+		}
+	}
+	for _, name := range []string{"M6x1", "unc_1/4"} {
+		k := ThreadLookup(name)
+		// scale the lengths with the thread size
+		l := 5.0 * k.Radius
+		bolt := Bolt3D(&BoltParms{name, "socket", 0.02 * k.Radius, 3 * l, l})
+		// nut in the middle of the thread (a whole number of pitches from the thread center)
+		nut := Nut3D(&NutParms{name, "knurl", 0.02 * k.Radius, 3 * k.Pitch})
+		nut = Transform3D(nut, Translate3d(V3{0, 0, 2 * l}))
+		bb := nut.BoundingBox()
+		for _, p := range bb.RandomSet(5000) {
+			if Max(bolt.Evaluate(p), nut.Evaluate(p)) < -0.001*k.Radius {
+				t.Logf("%s %v\n", name, p)
+				t.Error("FAIL")
+				break
+			}
+		}
+	}
+	w := BoltWasher3D(&WasherParms{Thread: "M8x1.25"})
+	// ISO 7089 M8: 8.4 x 16 x 1.6
+	if Abs(w.BoundingBox().Max.X-8) > 0.01 || Abs(w.BoundingBox().Size().Z-1.6) > 0.01 || w.Evaluate(V3{4.2, 0, 0}) <= 0 {
+		t.Error("FAIL")
+	}
+	if _, err := NewNut3D(&NutParms{"M8x1.25", "square", 0, 0}); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_ScrewBoss(t *testing.T) {
+	k := InsertLookup("M3x5.7")
+	h := InsertHole3D(k, 10)
+	// insert hole at the top, screw clearance hole below
+	if h.Evaluate(V3{1.9, 0, 4}) >= 0 || h.Evaluate(V3{1.9, 0, -4}) <= 0 {
+		t.Error("FAIL")
+	}
+	boss := ScrewBoss3D(&ScrewBossParms{Insert: "M3x5.7", Height: 12, HoleDepth: 8, NumberGussets: 4})
+	// wall around the insert, open at the top, solid below the hole
+	if boss.Evaluate(V3{3, 0, 5}) >= 0 || boss.Evaluate(V3{0, 0, 5}) <= 0 || boss.Evaluate(V3{0, 0, -5}) >= 0 {
+		t.Error("FAIL")
+	}
+	// tap drill hole through the boss
+	boss = ScrewBoss3D(&ScrewBossParms{Thread: "M4x0.7", Height: 10})
+	if Abs(boss.Evaluate(V3{0, 0, 0})-0.5*3.3) > 0.001 {
+		t.Error("FAIL")
+	}
+	if _, err := NewScrewBoss3D(&ScrewBossParms{Insert: "M7x9", Height: 10}); err == nil {
+		t.Error("FAIL")
+	}
+	// a nut fits in the trap and slides out along the slot
+	trap := NutTrap3D(&NutTrapParms{Thread: "M3x0.5", Clearance: 0.2, SlotLength: 10, HoleLength: 20})
+	tp := ThreadLookup("M3x0.5")
+	nut := Nut3D(&NutParms{Thread: "M3x0.5", Style: "hex"})
+	for _, x := range []float64{0, 5} {
+		n := Transform3D(nut, Translate3d(V3{x, 0, 0}))
+		bb := n.BoundingBox()
+		for _, p := range bb.RandomSet(2000) {
+			if n.Evaluate(p) < 0 && trap.Evaluate(p) > 0 {
+				t.Error("FAIL")
+				break
+			}
+		}
+	}
+	if trap.Evaluate(V3{0, 0, 8}) >= 0 || trap.Evaluate(V3{0, tp.Radius + 0.3, 8}) <= 0 {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_SnapFit(t *testing.T) {
+	k := &SnapFitParms{
+		Length:    15,
+		Thickness: 1.5,
+		Width:     6,
+		Undercut:  1,
+		Material:  "PETG",
+	}
+	s, err := MakeSnapFit(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 1.5 * 1.5 * 1 / 15^2
+	if Abs(s.Strain-0.01) > 1e-9 || !math.IsInf(s.SeparationForce, 1) {
+		t.Error("FAIL")
+	}
+	// P = (6 * 1.5^2 / 6) * 2100 * 0.01 / 15
+	if Abs(s.Force-3.15) > 1e-9 || s.MatingForce <= s.Force {
+		t.Error("FAIL")
+	}
+	// the hook tip is at the undercut depth
+	if Abs(s.Hook.Evaluate(V3{2.5, 0, 15})) > 1e-6 || s.Hook.Evaluate(V3{0.75, 0, 5}) >= 0 {
+		t.Error("FAIL")
+	}
+	// too short for PLA
+	k.Material = "PLA"
+	k.Length = 8
+	if _, err := MakeSnapFit(k); err == nil {
+		t.Error("FAIL")
+	}
+	if Abs(SnapFitStrain(SnapFitLength(1.5, 1, 0.02), 1.5, 1)-0.02) > 1e-9 {
+		t.Error("FAIL")
+	}
+}
+
+func Test_Hinge(t *testing.T) {
+	k := &HingeParms{
+		Length:        30,
+		Diameter:      6,
+		PinDiameter:   3,
+		Knuckles:      5,
+		Clearance:     0.4,
+		LeafWidth:     15,
+		LeafThickness: 2,
+	}
+	s := Hinge3D(k)
+	// the leaves are separate for any hinge angle
+	for _, a := range []float64{0, 45, 90, 135} {
+		leaf1 := Transform3D(s[1], RotateX(DtoR(a)))
+		bb := s[0].BoundingBox().Extend(leaf1.BoundingBox())
+		for _, p := range bb.RandomSet(20000) {
+			if Max(s[0].Evaluate(p), leaf1.Evaluate(p)) < 0 {
+				t.Logf("%f %v\n", a, p)
+				t.Error("FAIL")
+				break
+			}
+		}
+	}
+	// the pin is in the middle knuckle of the other leaf
+	if s[0].Evaluate(V3{0, 0, 0}) >= 0 || s[1].Evaluate(V3{0, 0, 0}) <= 0 {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Shafts(t *testing.T) {
+	k := KeyLookup("din6885", 20)
+	if k.Width != 6 || k.ShaftDepth != 3.5 || k.HubDepth != 2.8 {
+		t.Error("FAIL")
+	}
+	if _, err := KeyLookupErr("ansi", 10); err == nil {
+		t.Error("FAIL")
+	}
+	c := 0.1
+	shafts := []SDF2{
+		DShaft2D(5, 4.5),
+		KeyedShaft2D(20, k),
+		StraightSplineShaft2D(6, 23, 26, 6),
+		InvoluteSplineShaft2D(16, 1.25),
+	}
+	bores := []SDF2{
+		DBore2D(5, 4.5, c),
+		KeyedBore2D(20, k, c),
+		StraightSplineBore2D(6, 23, 26, 6, c),
+		InvoluteSplineBore2D(16, 1.25, c),
+	}
+	for i := range shafts {
+		bb := bores[i].BoundingBox().ScaleAboutCenter(1.2)
+		for _, p := range bb.RandomSet(5000) {
+			// the shaft is inside the bore with clearance
+			if shafts[i].Evaluate(p) < 0 && bores[i].Evaluate(p) > -0.99*c {
+				t.Logf("%d %v\n", i, p)
+				t.Error("FAIL")
+				break
+			}
+		}
+	}
+	// the flat, keyseat and first tooth are on the +y axis
+	if Abs(shafts[0].Evaluate(V2{0, 2})) > 1e-9 || Abs(shafts[1].Evaluate(V2{0, 6.5})) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if Abs(shafts[2].Evaluate(V2{0, 13})) > 1e-9 || Abs(shafts[3].Evaluate(V2{0, 10.625})) > 1e-6 {
+		t.Error("FAIL")
+	}
+	// the hub fills the space between the spline teeth
+	if bores[2].Evaluate(PolarToXY(12.5, DtoR(60))) <= 0 {
+		t.Error("FAIL")
+	}
+	// the hub keyway depth
+	if Abs(bores[1].Evaluate(V2{0, 10 + 2.8 + c})) > 1e-9 {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_CamMotion(t *testing.T) {
+	// synthesize cams and check the simulated follower motion
+	for _, law := range []string{"cycloidal", "harmonic", "polynomial"} {
+		lift, err := MakeCamLift(&CamLiftParms{
+			Law:       law,
+			Lift:      10,
+			Rise:      DtoR(120),
+			HighDwell: DtoR(30),
+			Return:    DtoR(120),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, follower := range []string{"knife", "roller", "flat"} {
+			cam, err := MakeCamProfile(&CamProfileParms{
+				BaseRadius:     30,
+				Lift:           lift,
+				Follower:       follower,
+				FollowerRadius: 8,
+				Facets:         2000,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := CamFollowerMotion(cam, follower, 8, 360)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, theta := range m.Angle {
+				if Abs(m.Lift[i]-lift(theta)) > 0.01 {
+					t.Logf("%s %s %f: %f %f\n", law, follower, RtoD(theta), m.Lift[i], lift(theta))
+					t.Error("FAIL")
+					break
+				}
+			}
+			// peak velocity at mid rise (per radian)
+			v := m.Velocity[60]
+			var v0 float64
+			switch law {
+			case "cycloidal":
+				v0 = 2 * 10 / DtoR(120)
+			case "harmonic":
+				v0 = 0.5 * PI * 10 / DtoR(120)
+			case "polynomial":
+				v0 = 1.875 * 10 / DtoR(120)
+			}
+			if Abs(v-v0) > 0.01*v0 {
+				t.Logf("%s %s: %f %f\n", law, follower, v, v0)
+				t.Error("FAIL")
+			}
+		}
+	}
+	// a large roller undercuts a small cam
+	lift, _ := MakeCamLift(&CamLiftParms{Law: "harmonic", Lift: 20, Rise: DtoR(60), Return: DtoR(60)})
+	_, err := MakeCamProfile(&CamProfileParms{BaseRadius: 10, Lift: lift, Follower: "roller", FollowerRadius: 20, Facets: 360})
+	if err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Bearing(t *testing.T) {
+	b := BearingLookup("608")
+	if b.Bore != 8 || b.OuterDiameter != 22 || b.Width != 7 || Abs(b.HousingShoulder-19.2) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if BearingLookup("LM8UU").Width != 24 {
+		t.Error("FAIL")
+	}
+	// press fit seat with a shoulder hole
+	s := BearingSeat3D(&BearingSeatParms{Bearing: "608", Fit: "press", Allowance: 0.1, Length: 10})
+	if Abs(s.Evaluate(V3{10.95, 0, -3.5})) > 1e-9 || s.Evaluate(V3{10, 0, -8}) <= 0 || s.Evaluate(V3{9, 0, -8}) >= 0 {
+		t.Error("FAIL")
+	}
+	// slip fit shaft with a shoulder
+	s = BearingShaft3D(&BearingShaftParms{Bearing: "608", Fit: "slip", Allowance: 0.1, ShoulderLength: 5})
+	if Abs(s.Evaluate(V3{3.95, 0, 3.5})) > 1e-9 || Abs(s.Evaluate(V3{5.4, 0, -2.5})) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if _, err := NewBearingShaft3D(&BearingShaftParms{Bearing: "LM8UU", Fit: "slip", ShoulderLength: 5}); err == nil {
+		t.Error("FAIL")
+	}
+	if _, err := NewBearingSeat3D(&BearingSeatParms{Bearing: "608", Fit: "tight"}); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Knurl(t *testing.T) {
+	if Abs(KnurlPitchLookup("ansi_96").Pitch-PI/96) > 1e-12 {
+		t.Error("FAIL")
+	}
+	// straight knurl on a cylinder, 30 grooves
+	pitch := TAU * 10 / 30
+	depth := 0.3 * pitch
+	cylinder := Box2D(V2{20, 20}, 0)
+	s := KnurledRevolve3D(cylinder, &KnurlParms{Pattern: "straight", Pitch: pitch, Min: -5, Max: 5})
+	groove := PI / 30
+	if Abs(s.Evaluate(V3{10, 0, 0})) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if s.Evaluate(V3{10 - depth, 0, 0}) >= 0 {
+		t.Error("FAIL")
+	}
+	p := V3{math.Cos(groove), math.Sin(groove), 0}.MulScalar(10 - depth)
+	if Abs(s.Evaluate(p)) > 1e-9 {
+		t.Error("FAIL")
+	}
+	// no groove outside the knurl
+	p.Z = 8
+	if s.Evaluate(p) >= 0 {
+		t.Error("FAIL")
+	}
+	// diamond knurl on a cone, the depth follows the surface
+	cone := Polygon2D([]V2{{0, -10}, {10, -10}, {5, 10}, {0, 10}})
+	s = KnurledRevolve3D(cone, &KnurlParms{Pattern: "diamond", Pitch: pitch, Angle: DtoR(30)})
+	n := V2{1, 0.25}.Normalize().MulScalar(depth)
+	if Abs(s.Evaluate(V3{7.5, 0, 0})) > 1e-9 {
+		t.Error("FAIL")
+	}
+	// the center of a right hand groove
+	c := math.Floor(TAU*10*math.Cos(DtoR(30))/pitch + 0.5)
+	groove = PI/c - n.Y*math.Tan(DtoR(30))/10
+	x := 7.5 - n.X
+	p = V3{x * math.Cos(groove), x * math.Sin(groove), -n.Y}
+	if Abs(s.Evaluate(p)) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if _, err := NewKnurledRevolve3D(cone, &KnurlParms{Pattern: "cross", Pitch: 1}); err == nil {
+		t.Error("FAIL")
+	}
+	if _, err := NewKnurledRevolve3D(cone, &KnurlParms{Pattern: "straight", Pitch: 1, Depth: 0.6}); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_Enclosure(t *testing.T) {
+	k := EnclosureParms{
+		Board:       Box2D(V2{50, 30}, 0),
+		Holes:       []V2{{-22, -12}, {22, 12}},
+		Standoff:    StandoffParms{PillarDiameter: 6, HoleDiameter: 2.5},
+		Connectors:  []PCBConnector{{Connector: "usb_c", Edge: "+x", Position: 5}},
+		BoardHeight: 5,
+		Height:      20,
+		Wall:        2,
+		Clearance:   1,
+	}
+	s := Enclosure3D(&k)
+	base, lid := s[0], s[1]
+	// usb-c cut-out through the +x wall at the connector height
+	z := 5 + 1.6 + 1.63
+	if base.Evaluate(V3{27, 9, z}) <= 0 || base.Evaluate(V3{27, -5, z}) >= 0 || base.Evaluate(V3{27, 5, z + 2.5}) >= 0 {
+		t.Error("FAIL")
+	}
+	// standoff under the mounting hole
+	if base.Evaluate(V3{24.5, 12, 4.9}) >= 0 || base.Evaluate(V3{24.5, 12, 5.1}) <= 0 || base.Evaluate(V3{22, 12, 4}) <= 0 {
+		t.Error("FAIL")
+	}
+	// the lid sits on the walls
+	if lid.Evaluate(V3{0, 0, 21}) >= 0 || lid.Evaluate(V3{0, 0, 19}) <= 0 {
+		t.Error("FAIL")
+	}
+	// the connector does not fit under the lid
+	k.Height = 8
+	if _, err := NewEnclosure3D(&k); err == nil {
+		t.Error("FAIL")
+	}
+	k.Height = 20
+	k.Holes = []V2{{30, 0}}
+	if _, err := NewEnclosure3D(&k); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func Test_RackPanels(t *testing.T) {
+	// 3U 10HP Eurorack panel with a jack
+	s := EurorackPanel2D(&EurorackParms{
+		Format:     "3U",
+		HP:         10,
+		Components: []PanelComponent{{Component: "jack_3.5", Position: V2{0, -40}}},
+	})
+	bb := s.BoundingBox()
+	if Abs(bb.Size().X-50.5) > 1e-9 || Abs(bb.Size().Y-128.5) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if Abs(s.Evaluate(V2{-17.75, 61.25})-1.6) > 1e-9 || Abs(s.Evaluate(V2{17.81, -61.25})-1.6) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if Abs(s.Evaluate(V2{0, -40})-3.0) > 1e-9 {
+		t.Error("FAIL")
+	}
+	if _, err := NewEurorackPanel2D(&EurorackParms{Format: "3U", HP: 4, Components: []PanelComponent{{Component: "pot_24mm"}}}); err == nil {
+		t.Error("FAIL")
+	}
+	// 2U rack panel with slotted holes
+	s = RackPanel2D(&RackParms{U: 2, SlotLength: 3})
+	bb = s.BoundingBox()
+	if Abs(bb.Size().X-482.6) > 1e-9 || Abs(bb.Size().Y-88.11) > 1e-9 {
+		t.Error("FAIL")
+	}
+	for _, y := range []float64{-38.1, -6.35, 6.35, 38.1} {
+		if Abs(s.Evaluate(V2{232.55 + 1.5, y})-3.25) > 1e-9 || Abs(s.Evaluate(V2{-232.55 - 1.5, y})-3.25) > 1e-9 {
+			t.Error("FAIL")
+		}
+	}
+	if _, err := NewRackPanel2D(&RackParms{U: 1, SlotLength: 12}); err == nil {
+		t.Error("FAIL")
+	}
+}
+
+//-----------------------------------------------------------------------------
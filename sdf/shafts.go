@@ -0,0 +1,268 @@
+//-----------------------------------------------------------------------------
+/*
+
+Shafts and Bores
+
+2D profiles for shafts and the matching bores of gears, pulleys, etc.
+The bore profiles are for cutting out of a part, the clearance is added to
+all of the fitting surfaces.
+
+D-shafts: a round shaft with a flat. The across dimension is the distance
+from the flat to the opposite side of the shaft.
+
+Keyways: parallel keys to DIN 6885 A (mm) and ANSI B17.1 square keys (inch).
+The key size is looked up by the shaft diameter.
+
+Splines:
+
+Straight sided splines (ISO 14) with n teeth of a given width, a minor
+diameter and a major diameter.
+
+Involute splines with a 30 degree pressure angle and flat root (similar to
+ANSI B92.1 and DIN 5480). For module m and n teeth:
+
+major diameter = m * (n + 1)
+minor diameter = m * (n - 1.35)
+
+In all cases the key, flat or first tooth is on the +y axis.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+//-----------------------------------------------------------------------------
+// D-Shafts
+
+// DShaft2D returns the profile for a D-shaft.
+func DShaft2D(
+	diameter float64, // shaft diameter
+	across float64, // distance from the flat to the opposite side
+) SDF2 {
+	if across <= 0.5*diameter || across > diameter {
+		panic("invalid D-shaft flat")
+	}
+	return Cut2D(Circle2D(0.5*diameter), V2{0, across - 0.5*diameter}, V2{1, 0})
+}
+
+// DBore2D returns the bore profile for a D-shaft.
+func DBore2D(
+	diameter float64, // shaft diameter
+	across float64, // distance from the flat to the opposite side
+	clearance float64, // radial clearance
+) SDF2 {
+	return Offset2D(DShaft2D(diameter, across), clearance)
+}
+
+//-----------------------------------------------------------------------------
+// Key Database - lookup standard parallel keys by shaft diameter
+
+type KeyParameters struct {
+	Standard    string  // "din6885" or "ansi"
+	MinDiameter float64 // minimum shaft diameter (exclusive)
+	MaxDiameter float64 // maximum shaft diameter (inclusive)
+	Width       float64 // key width
+	Height      float64 // key height
+	ShaftDepth  float64 // keyseat depth in the shaft
+	HubDepth    float64 // keyway depth in the hub
+	Units       string  // "inch" or "mm"
+}
+
+type KeyDatabase []*KeyParameters
+
+var key_db = Init_KeyLookup()
+
+// DINAdd adds a DIN 6885 A parallel key to the key database.
+func (m *KeyDatabase) DINAdd(
+	d0, d1 float64, // shaft diameter range
+	width, height float64, // key size
+	t1, t2 float64, // shaft and hub depths
+) {
+	k := KeyParameters{}
+	k.Standard = "din6885"
+	k.MinDiameter = d0
+	k.MaxDiameter = d1
+	k.Width = width
+	k.Height = height
+	k.ShaftDepth = t1
+	k.HubDepth = t2
+	k.Units = "mm"
+	*m = append(*m, &k)
+}
+
+// ANSIAdd adds an ANSI B17.1 square key to the key database.
+func (m *KeyDatabase) ANSIAdd(
+	d0, d1 float64, // shaft diameter range
+	width float64, // key size
+) {
+	k := KeyParameters{}
+	k.Standard = "ansi"
+	k.MinDiameter = d0
+	k.MaxDiameter = d1
+	k.Width = width
+	k.Height = width
+	k.ShaftDepth = width / 2.0
+	k.HubDepth = width / 2.0
+	k.Units = "inch"
+	*m = append(*m, &k)
+}
+
+func Init_KeyLookup() KeyDatabase {
+	m := KeyDatabase{}
+	// DIN 6885 A
+	m.DINAdd(6, 8, 2, 2, 1.2, 1.0)
+	m.DINAdd(8, 10, 3, 3, 1.8, 1.4)
+	m.DINAdd(10, 12, 4, 4, 2.5, 1.8)
+	m.DINAdd(12, 17, 5, 5, 3.0, 2.3)
+	m.DINAdd(17, 22, 6, 6, 3.5, 2.8)
+	m.DINAdd(22, 30, 8, 7, 4.0, 3.3)
+	m.DINAdd(30, 38, 10, 8, 5.0, 3.3)
+	m.DINAdd(38, 44, 12, 8, 5.0, 3.3)
+	m.DINAdd(44, 50, 14, 9, 5.5, 3.8)
+	m.DINAdd(50, 58, 16, 10, 6.0, 4.3)
+	m.DINAdd(58, 65, 18, 11, 7.0, 4.4)
+	m.DINAdd(65, 75, 20, 12, 7.5, 4.9)
+	m.DINAdd(75, 85, 22, 14, 9.0, 5.4)
+	m.DINAdd(85, 95, 25, 14, 9.0, 5.4)
+	m.DINAdd(95, 110, 28, 16, 10.0, 6.4)
+	// ANSI B17.1
+	m.ANSIAdd(5.0/16.0, 7.0/16.0, 3.0/32.0)
+	m.ANSIAdd(7.0/16.0, 9.0/16.0, 1.0/8.0)
+	m.ANSIAdd(9.0/16.0, 7.0/8.0, 3.0/16.0)
+	m.ANSIAdd(7.0/8.0, 1.25, 1.0/4.0)
+	m.ANSIAdd(1.25, 1.375, 5.0/16.0)
+	m.ANSIAdd(1.375, 1.75, 3.0/8.0)
+	m.ANSIAdd(1.75, 2.25, 1.0/2.0)
+	m.ANSIAdd(2.25, 2.75, 5.0/8.0)
+	m.ANSIAdd(2.75, 3.25, 3.0/4.0)
+	m.ANSIAdd(3.25, 3.75, 7.0/8.0)
+	m.ANSIAdd(3.75, 4.5, 1.0)
+	return m
+}
+
+// lookup the key for a shaft diameter
+func KeyLookupErr(standard string, diameter float64) (*KeyParameters, error) {
+	found := false
+	for _, k := range key_db {
+		if k.Standard != standard {
+			continue
+		}
+		found = true
+		if diameter > k.MinDiameter && diameter <= k.MaxDiameter {
+			return k, nil
+		}
+	}
+	if !found {
+		return nil, param_error("KeyLookup", "standard", standard, "key standard not found")
+	}
+	return nil, param_error("KeyLookup", "diameter", diameter, "no key for this shaft diameter")
+}
+
+// lookup the key for a shaft diameter (panic on error)
+func KeyLookup(standard string, diameter float64) *KeyParameters {
+	k, err := KeyLookupErr(standard, diameter)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+//-----------------------------------------------------------------------------
+// Keyed Shafts
+
+// KeyedShaft2D returns the profile for a shaft with a keyseat.
+func KeyedShaft2D(
+	diameter float64, // shaft diameter
+	k *KeyParameters, // key parameters
+) SDF2 {
+	r := 0.5 * diameter
+	keyseat := Box2D(V2{k.Width, 2.0 * k.ShaftDepth}, 0)
+	keyseat = Transform2D(keyseat, Translate2d(V2{0, r}))
+	return Difference2D(Circle2D(r), keyseat)
+}
+
+// KeyedBore2D returns the bore profile for a keyed shaft.
+func KeyedBore2D(
+	diameter float64, // shaft diameter
+	k *KeyParameters, // key parameters
+	clearance float64, // bore and keyway clearance
+) SDF2 {
+	r := 0.5*diameter + clearance
+	h := r + k.HubDepth
+	keyway := Box2D(V2{k.Width + 2.0*clearance, h}, 0)
+	keyway = Transform2D(keyway, Translate2d(V2{0, 0.5 * h}))
+	return Union2D(Circle2D(r), keyway)
+}
+
+//-----------------------------------------------------------------------------
+// Splines
+
+// straight_spline returns a straight sided spline profile.
+func straight_spline(n int, r_minor, r_major, width float64) SDF2 {
+	tooth := Box2D(V2{r_major, width}, 0)
+	tooth = Transform2D(tooth, Translate2d(V2{0.5 * r_major, 0}))
+	// put a tooth on the +y axis
+	teeth := Transform2D(RotateCopy2D(tooth, n), Rotate2d(0.5*PI))
+	return Intersect2D(Circle2D(r_major), Union2D(Circle2D(r_minor), teeth))
+}
+
+// StraightSplineShaft2D returns the profile for a straight sided spline shaft.
+func StraightSplineShaft2D(
+	number_teeth int, // number of spline teeth
+	minor_diameter float64, // minor (root) diameter
+	major_diameter float64, // major (outside) diameter
+	width float64, // tooth width
+) SDF2 {
+	if number_teeth < 2 {
+		panic("invalid number of teeth")
+	}
+	if minor_diameter >= major_diameter {
+		panic("minor diameter >= major diameter")
+	}
+	return straight_spline(number_teeth, 0.5*minor_diameter, 0.5*major_diameter, width)
+}
+
+// StraightSplineBore2D returns the bore profile for a straight sided spline shaft.
+func StraightSplineBore2D(
+	number_teeth int, // number of spline teeth
+	minor_diameter float64, // minor (root) diameter
+	major_diameter float64, // major (outside) diameter
+	width float64, // tooth width
+	clearance float64, // clearance
+) SDF2 {
+	if number_teeth < 2 {
+		panic("invalid number of teeth")
+	}
+	if minor_diameter >= major_diameter {
+		panic("minor diameter >= major diameter")
+	}
+	return straight_spline(number_teeth, 0.5*minor_diameter+clearance, 0.5*major_diameter+clearance, width+2.0*clearance)
+}
+
+// InvoluteSplineShaft2D returns the profile for a 30 degree involute spline shaft.
+func InvoluteSplineShaft2D(
+	number_teeth int, // number of spline teeth
+	spline_module float64, // pitch circle diameter / number of spline teeth
+) SDF2 {
+	if number_teeth < 6 {
+		panic("invalid number of teeth")
+	}
+	n := float64(number_teeth)
+	gear := InvoluteGear2D(number_teeth, spline_module, DtoR(30), 0, 0, 0)
+	// put a tooth on the +y axis
+	gear = Transform2D(gear, Rotate2d(0.5*PI))
+	r_minor := 0.5 * spline_module * (n - 1.35)
+	r_major := 0.5 * spline_module * (n + 1)
+	return Intersect2D(Circle2D(r_major), Union2D(Circle2D(r_minor), gear))
+}
+
+// InvoluteSplineBore2D returns the bore profile for a 30 degree involute spline shaft.
+func InvoluteSplineBore2D(
+	number_teeth int, // number of spline teeth
+	spline_module float64, // pitch circle diameter / number of spline teeth
+	clearance float64, // clearance
+) SDF2 {
+	return Offset2D(InvoluteSplineShaft2D(number_teeth, spline_module), clearance)
+}
+
+//-----------------------------------------------------------------------------
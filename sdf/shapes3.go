@@ -131,18 +131,31 @@ func Knurl3D(
 //-----------------------------------------------------------------------------
 
 // Return a washer.
-func Washer3D(
+func NewWasher3D(
 	t float64, // thickness
 	r_inner float64, // inner radius
 	r_outer float64, // outer radius
-) SDF3 {
+) (SDF3, error) {
 	if t <= 0 {
-		panic("t <= 0")
+		return nil, param_error("Washer3D", "t", t, "must be > 0")
 	}
 	if r_inner >= r_outer {
-		panic("r_inner >= r_outer")
+		return nil, param_error("Washer3D", "r_inner", r_inner, "must be < r_outer")
+	}
+	return Difference3D(Cylinder3D(t, r_outer, 0), Cylinder3D(t, r_inner, 0)), nil
+}
+
+// Return a washer (panic on error).
+func Washer3D(
+	t float64, // thickness
+	r_inner float64, // inner radius
+	r_outer float64, // outer radius
+) SDF3 {
+	s, err := NewWasher3D(t, r_inner, r_outer)
+	if err != nil {
+		panic(err)
 	}
-	return Difference3D(Cylinder3D(t, r_outer, 0), Cylinder3D(t, r_inner, 0))
+	return s
 }
 
 //-----------------------------------------------------------------------------
@@ -220,6 +233,245 @@ func Standoffs3D(k *StandoffParms, positions V3Set) SDF3 {
 	return Union3D(s...)
 }
 
+//-----------------------------------------------------------------------------
+// Heat-set inserts
+
+type InsertParameters struct {
+	Name         string  // name of the insert
+	Thread       string  // name of the insert thread (see ThreadLookup)
+	Diameter     float64 // outside diameter of the insert
+	Length       float64 // length of the insert
+	HoleDiameter float64 // diameter of the hole for the insert
+}
+
+type InsertDatabase map[string]*InsertParameters
+
+var insert_db = Init_InsertLookup()
+
+// InsertAdd adds a heat-set insert to the insert database (dimensions in mm).
+func (m InsertDatabase) InsertAdd(
+	name string, // insert name
+	thread string, // thread name
+	diameter float64, // outside diameter
+	length float64, // insert length
+	hole float64, // hole diameter
+) {
+	k := InsertParameters{}
+	k.Name = name
+	k.Thread = thread
+	k.Diameter = diameter
+	k.Length = length
+	k.HoleDiameter = hole
+	m[name] = &k
+}
+
+func Init_InsertLookup() InsertDatabase {
+	m := make(InsertDatabase)
+	m.InsertAdd("M2x3", "M2x0.4", 3.6, 3, 3.2)
+	m.InsertAdd("M2x4", "M2x0.4", 3.6, 4, 3.2)
+	m.InsertAdd("M2.5x4", "M2.5x0.45", 4.0, 4, 3.6)
+	m.InsertAdd("M2.5x5.7", "M2.5x0.45", 4.0, 5.7, 3.6)
+	m.InsertAdd("M3x4", "M3x0.5", 4.6, 4, 4.0)
+	m.InsertAdd("M3x5.7", "M3x0.5", 4.6, 5.7, 4.0)
+	m.InsertAdd("M4x8.1", "M4x0.7", 6.3, 8.1, 5.6)
+	m.InsertAdd("M5x9.5", "M5x0.8", 7.1, 9.5, 6.4)
+	return m
+}
+
+// lookup the parameters for a heat-set insert by name
+func InsertLookupErr(name string) (*InsertParameters, error) {
+	k, ok := insert_db[name]
+	if !ok {
+		return nil, param_error("InsertLookup", "name", name, "insert name not found")
+	}
+	return k, nil
+}
+
+// lookup the parameters for a heat-set insert by name (panic on error)
+func InsertLookup(name string) *InsertParameters {
+	k, err := InsertLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// Heat-set insert hole.
+// The insert hole (with a small lead-in chamfer) is at the +z end, the rest
+// of the hole is a screw clearance hole.
+func InsertHole3D(
+	k *InsertParameters, // insert parameters
+	l float64, // total length
+) SDF3 {
+	t := ThreadLookup(k.Thread)
+	r := 0.5 * k.HoleDiameter
+	d := Min(l, k.Length+t.Radius)
+	// screw clearance hole below the insert
+	s := Cylinder3D(l, t.Radius, 0)
+	// insert hole with room for melted plastic at the bottom
+	s = Union3D(s, Transform3D(Cylinder3D(d, r, 0), Translate3d(V3{0, 0, 0.5 * (l - d)})))
+	// lead-in chamfer
+	ch := 0.5 * (k.Diameter - k.HoleDiameter)
+	s1 := Cone3D(ch, r, r+ch, 0)
+	s1 = Transform3D(s1, Translate3d(V3{0, 0, 0.5 * (l - ch)}))
+	return Union3D(s, s1)
+}
+
+//-----------------------------------------------------------------------------
+// Captive nut traps
+
+type NutTrapParms struct {
+	Thread     string  // name of thread (see ThreadLookup)
+	Clearance  float64 // fit clearance for the nut and screw
+	Depth      float64 // depth of the nut pocket (0 for the hex head height)
+	SlotLength float64 // length of the insertion slot along +x (0 for no slot)
+	HoleLength float64 // total length of the screw clearance hole (0 for no hole)
+}
+
+// NewNutTrap3D returns the hole geometry for a captive hex nut.
+// The nut pocket is centered on the origin with the hex axis on the z-axis.
+// The insertion slot runs along the +x axis between two of the hex flats.
+func NewNutTrap3D(k *NutTrapParms) (SDF3, error) {
+	t, err := ThreadLookupErr(k.Thread)
+	if err != nil {
+		return nil, err
+	}
+	if k.Clearance < 0 {
+		return nil, param_error("NutTrap3D", "Clearance", k.Clearance, "must be >= 0")
+	}
+	if k.Depth < 0 {
+		return nil, param_error("NutTrap3D", "Depth", k.Depth, "must be >= 0")
+	}
+	if k.SlotLength < 0 {
+		return nil, param_error("NutTrap3D", "SlotLength", k.SlotLength, "must be >= 0")
+	}
+	if k.HoleLength < 0 {
+		return nil, param_error("NutTrap3D", "HoleLength", k.HoleLength, "must be >= 0")
+	}
+	r, err := t.Hex_RadiusErr()
+	if err != nil {
+		return nil, err
+	}
+	r += k.Clearance / math.Cos(DtoR(30))
+	h := k.Depth
+	if h == 0 {
+		h, _ = t.Hex_HeightErr()
+		h += k.Clearance
+	}
+	// vertices on the x-axis, so the flats are parallel to the slot
+	s := Extrude3D(Polygon2D(Nagon(6, r)), h)
+	if k.SlotLength > 0 {
+		w := 2.0 * r * math.Cos(DtoR(30))
+		slot := Box3D(V3{k.SlotLength, w, h}, 0)
+		slot = Transform3D(slot, Translate3d(V3{0.5 * k.SlotLength, 0, 0}))
+		s = Union3D(s, slot)
+	}
+	if k.HoleLength > 0 {
+		s = Union3D(s, Cylinder3D(k.HoleLength, t.Radius+k.Clearance, 0))
+	}
+	return s, nil
+}
+
+// NutTrap3D returns the hole geometry for a captive hex nut (panic on error).
+func NutTrap3D(k *NutTrapParms) SDF3 {
+	s, err := NewNutTrap3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+// Screw bosses
+
+type ScrewBossParms struct {
+	Thread         string  // name of thread for a tapped hole (see ThreadLookup)
+	Insert         string  // name of heat-set insert (see InsertLookup), overrides Thread
+	Height         float64 // boss height
+	Diameter       float64 // boss diameter (0 for a default based on the hole)
+	HoleDepth      float64 // hole depth (0 for the boss height)
+	NumberGussets  int     // number of gussets
+	GussetHeight   float64 // gusset height (0 for half the boss height)
+	GussetDiameter float64 // overall gusset diameter (0 for twice the boss diameter)
+	GussetWidth    float64 // gusset width (0 for a quarter of the boss diameter)
+}
+
+// NewScrewBoss3D returns a screw boss for a tapped hole or heat-set insert.
+// The boss is centered on the origin with the hole at the +z end and the
+// gussets at the -z end.
+func NewScrewBoss3D(k *ScrewBossParms) (SDF3, error) {
+	if k.Height <= 0 {
+		return nil, param_error("ScrewBoss3D", "Height", k.Height, "must be > 0")
+	}
+	if k.Diameter < 0 {
+		return nil, param_error("ScrewBoss3D", "Diameter", k.Diameter, "must be >= 0")
+	}
+	if k.HoleDepth < 0 || k.HoleDepth > k.Height {
+		return nil, param_error("ScrewBoss3D", "HoleDepth", k.HoleDepth, "must be >= 0 and <= Height")
+	}
+	if k.NumberGussets < 0 {
+		return nil, param_error("ScrewBoss3D", "NumberGussets", k.NumberGussets, "must be >= 0")
+	}
+	depth := k.HoleDepth
+	if depth == 0 {
+		depth = k.Height
+	}
+
+	var hole SDF3
+	var d float64
+	if k.Insert != "" {
+		insert, err := InsertLookupErr(k.Insert)
+		if err != nil {
+			return nil, err
+		}
+		if depth < insert.Length {
+			return nil, param_error("ScrewBoss3D", "HoleDepth", depth, "must be >= insert length")
+		}
+		hole = InsertHole3D(insert, depth)
+		d = 2.0 * insert.HoleDiameter
+	} else {
+		t, err := ThreadLookupErr(k.Thread)
+		if err != nil {
+			return nil, err
+		}
+		hole = Cylinder3D(depth, 0.5*t.TapDrill, 0)
+		d = 5.0 * t.Radius
+	}
+	if k.Diameter != 0 {
+		d = k.Diameter
+	}
+
+	sp := &StandoffParms{
+		PillarHeight:   k.Height,
+		PillarDiameter: d,
+		NumberWebs:     k.NumberGussets,
+		WebHeight:      k.GussetHeight,
+		WebDiameter:    k.GussetDiameter,
+		WebWidth:       k.GussetWidth,
+	}
+	if sp.WebHeight == 0 {
+		sp.WebHeight = 0.5 * k.Height
+	}
+	if sp.WebDiameter == 0 {
+		sp.WebDiameter = 2.0 * d
+	}
+	if sp.WebWidth == 0 {
+		sp.WebWidth = 0.25 * d
+	}
+	s := Standoff3D(sp)
+	hole = Transform3D(hole, Translate3d(V3{0, 0, 0.5 * (k.Height - depth)}))
+	return Difference3D(s, hole), nil
+}
+
+// ScrewBoss3D returns a screw boss (panic on error).
+func ScrewBoss3D(k *ScrewBossParms) SDF3 {
+	s, err := NewScrewBoss3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 //-----------------------------------------------------------------------------
 
 type box_tab_parms struct {
@@ -347,37 +599,40 @@ type PanelBoxParms struct {
 	SideTabs   string  // tab pattern b/B (bottom) t/T (top) . (empty)
 }
 
-// PanelBox3D returns a 4 part panel box
-func PanelBox3D(k *PanelBoxParms) []SDF3 {
+// NewPanelBox3D returns a 4 part panel box
+func NewPanelBox3D(k *PanelBoxParms) ([]SDF3, error) {
 	// sanity checks
 	if k.Size.X <= 0 || k.Size.Y <= 0 || k.Size.Z <= 0 {
-		panic("invalid box size")
+		return nil, param_error("PanelBox3D", "Size", k.Size, "must be > 0")
 	}
 	if k.Wall <= 0 {
-		panic("invalid wall size")
+		return nil, param_error("PanelBox3D", "Wall", k.Wall, "must be > 0")
 	}
 	if k.Panel <= 0 {
-		panic("invalid panel size")
+		return nil, param_error("PanelBox3D", "Panel", k.Panel, "must be > 0")
 	}
 	if k.Rounding < 0 {
-		panic("invalid rounding size")
+		return nil, param_error("PanelBox3D", "Rounding", k.Rounding, "must be >= 0")
+	}
+	if k.FrontInset < 0 {
+		return nil, param_error("PanelBox3D", "FrontInset", k.FrontInset, "must be >= 0")
 	}
-	if k.FrontInset < 0 || k.BackInset < 0 {
-		panic("invalid front/back inset size")
+	if k.BackInset < 0 {
+		return nil, param_error("PanelBox3D", "BackInset", k.BackInset, "must be >= 0")
 	}
 	if k.Clearance < 0 || k.Clearance > 1.0 {
-		panic("invalid clearance")
+		return nil, param_error("PanelBox3D", "Clearance", k.Clearance, "must be >= 0 and <= 1")
 	}
 	if k.Clearance == 0 {
 		// set a default
 		k.Clearance = 0.05
 	}
 	if k.Hole < 0 {
-		panic("invalid screw hole size")
+		return nil, param_error("PanelBox3D", "Hole", k.Hole, "must be >= 0")
 	}
 	if k.Hole > 0 {
 		if !strings.Contains(k.SideTabs, "T") && !strings.Contains(k.SideTabs, "B") {
-			panic("screw hole is non-zero, but there are no screw tabs (T/B)")
+			return nil, param_error("PanelBox3D", "SideTabs", k.SideTabs, "screw hole is non-zero, but there are no screw tabs (T/B)")
 		}
 	}
 
@@ -386,7 +641,7 @@ func PanelBox3D(k *PanelBoxParms) []SDF3 {
 
 	mid_z := k.Size.Z - k.FrontInset - k.BackInset - 2.0*(panel_gap+2.0*k.Wall)
 	if mid_z <= 0.0 {
-		panic("the front and back panel depths exceed the total box length")
+		return nil, param_error("PanelBox3D", "Size", k.Size, "the front and back panel depths exceed the total box length")
 	}
 
 	outer_size := V2{k.Size.X, k.Size.Y}
@@ -524,7 +779,16 @@ func PanelBox3D(k *PanelBoxParms) []SDF3 {
 		}
 	}
 
-	return []SDF3{panel, top, bottom}
+	return []SDF3{panel, top, bottom}, nil
+}
+
+// PanelBox3D returns a 4 part panel box (panic on error)
+func PanelBox3D(k *PanelBoxParms) []SDF3 {
+	s, err := NewPanelBox3D(k)
+	if err != nil {
+		panic(err)
+	}
+	return s
 }
 
 //-----------------------------------------------------------------------------
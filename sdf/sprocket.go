@@ -0,0 +1,231 @@
+//-----------------------------------------------------------------------------
+/*
+
+Roller Chain Sprockets
+
+The tooth form is the ISO 606 maximum tooth gap form:
+
+d = pitch / sin(PI / z) (pitch diameter)
+ri = 0.505 * d1 + 0.069 * cbrt(d1) (roller seating radius)
+alpha = 120 - 90 / z (roller seating angle, degrees)
+re = 0.008 * d1 * (z^2 + 180) (tooth flank radius)
+da = d + pitch * (1 - 1.6 / z) - d1 (tip diameter)
+bf = 0.93 * b1 (tooth width, b1 <= 12.7mm) or 0.95 * b1
+
+where z is the number of teeth, d1 is the roller diameter and b1 is the
+inner width of the chain.
+
+The maximum gap form gives the most clearance for the chain rollers, which
+suits printed sprockets. ANSI chains use the same form with the ANSI
+chain dimensions.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+// Chain Database - lookup standard roller chains by name
+
+type ChainParameters struct {
+	Name           string  // name of the roller chain
+	Pitch          float64 // pin to pin distance
+	RollerDiameter float64 // diameter of the rollers (or bushings)
+	InnerWidth     float64 // width between the inner plates
+}
+
+type ChainDatabase map[string]*ChainParameters
+
+var chain_db = Init_ChainLookup()
+
+// ChainAdd adds a roller chain to the chain database (dimensions in mm).
+func (m ChainDatabase) ChainAdd(
+	name string, // chain name
+	pitch float64, // pin to pin distance
+	roller float64, // roller diameter
+	width float64, // inner width
+) {
+	c := ChainParameters{}
+	c.Name = name
+	c.Pitch = pitch
+	c.RollerDiameter = roller
+	c.InnerWidth = width
+	m[name] = &c
+}
+
+func Init_ChainLookup() ChainDatabase {
+	m := make(ChainDatabase)
+	// ANSI B29.1
+	m.ChainAdd("ANSI_25", 6.35, 3.30, 3.18)
+	m.ChainAdd("ANSI_35", 9.525, 5.08, 4.77)
+	m.ChainAdd("ANSI_40", 12.7, 7.92, 7.85)
+	m.ChainAdd("ANSI_41", 12.7, 7.77, 6.25)
+	m.ChainAdd("ANSI_50", 15.875, 10.16, 9.40)
+	m.ChainAdd("ANSI_60", 19.05, 11.91, 12.57)
+	// ISO 606 (European)
+	m.ChainAdd("ISO_05B", 8, 5.0, 3.0)
+	m.ChainAdd("ISO_06B", 9.525, 6.35, 5.72)
+	m.ChainAdd("ISO_08B", 12.7, 8.51, 7.75)
+	m.ChainAdd("ISO_10B", 15.875, 10.16, 9.65)
+	m.ChainAdd("ISO_12B", 19.05, 12.07, 11.68)
+	return m
+}
+
+// lookup the parameters for a roller chain by name
+func ChainLookupErr(name string) (*ChainParameters, error) {
+	c, ok := chain_db[name]
+	if !ok {
+		return nil, param_error("ChainLookup", "name", name, "chain name not found")
+	}
+	return c, nil
+}
+
+// lookup the parameters for a roller chain by name (panic on error)
+func ChainLookup(name string) *ChainParameters {
+	c, err := ChainLookupErr(name)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// PitchDiameter returns the pitch diameter of a sprocket for this chain.
+func (c *ChainParameters) PitchDiameter(number_teeth int) float64 {
+	return c.Pitch / math.Sin(PI/float64(number_teeth))
+}
+
+// TipDiameter returns the tip diameter of a sprocket for this chain.
+func (c *ChainParameters) TipDiameter(number_teeth int) float64 {
+	z := float64(number_teeth)
+	return c.PitchDiameter(number_teeth) + c.Pitch*(1-1.6/z) - c.RollerDiameter
+}
+
+// ToothWidth returns the tooth width of a sprocket for this chain.
+func (c *ChainParameters) ToothWidth() float64 {
+	if c.InnerWidth <= 12.7 {
+		return 0.93 * c.InnerWidth
+	}
+	return 0.95 * c.InnerWidth
+}
+
+//-----------------------------------------------------------------------------
+// 2D Sprocket
+
+type SprocketSDF2 struct {
+	theta float64 // angular tooth pitch
+	c     V2      // roller seating center (for the gap on the x-axis)
+	ri    float64 // roller seating radius
+	re    float64 // tooth flank radius
+	f     V2      // flank center (for the +y flank of the gap on the x-axis)
+	x0    float64 // x position of the chord between the seating/flank tangent points
+	tip   float64 // tip radius
+	bb    Box2    // bounding box
+}
+
+// Sprocket2D returns the 2D profile for a roller chain sprocket.
+// A tooth gap (roller seat) is centered on the x-axis.
+func Sprocket2D(
+	chain *ChainParameters, // chain parameters
+	number_teeth int, // number of sprocket teeth
+) SDF2 {
+	if number_teeth < 6 {
+		panic("invalid number of teeth")
+	}
+	z := float64(number_teeth)
+	d1 := chain.RollerDiameter
+	s := SprocketSDF2{}
+	s.theta = TAU / z
+	s.c = V2{0.5 * chain.PitchDiameter(number_teeth), 0}
+	s.ri = 0.505*d1 + 0.069*math.Cbrt(d1)
+	s.re = 0.008 * d1 * (z*z + 180)
+	alpha := DtoR(120 - 90/z)
+	// the seating arc ends at +/- alpha/2 from the bottom of the seat
+	u := V2{-math.Cos(0.5 * alpha), math.Sin(0.5 * alpha)}
+	s.f = s.c.Add(u.MulScalar(s.ri + s.re))
+	s.x0 = s.c.X + u.X*s.ri
+	s.tip = 0.5 * chain.TipDiameter(number_teeth)
+	s.bb = Box2{V2{-s.tip, -s.tip}, V2{s.tip, s.tip}}
+	return &s
+}
+
+// gap returns the distance to the tooth gap centered on the x-axis.
+func (s *SprocketSDF2) gap(p V2) float64 {
+	// roller seat
+	d0 := p.Sub(s.c).Length() - s.ri
+	// between the tooth flanks
+	p1 := V2{p.X, Abs(p.Y)}
+	d1 := Max(s.x0-p.X, s.re-p1.Sub(s.f).Length())
+	return Min(d0, d1)
+}
+
+// Evaluate returns the minimum distance to the sprocket.
+func (s *SprocketSDF2) Evaluate(p V2) float64 {
+	// map p to the sector for the gap on the x-axis
+	l := p.Length()
+	theta := SawTooth(math.Atan2(p.Y, p.X), s.theta)
+	// check the neighbouring gaps
+	d := math.MaxFloat64
+	for i := -1; i <= 1; i++ {
+		d = Min(d, s.gap(PolarToXY(l, theta+float64(i)*s.theta)))
+	}
+	return Max(l-s.tip, -d)
+}
+
+// BoundingBox returns the bounding box for the sprocket.
+func (s *SprocketSDF2) BoundingBox() Box2 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
+// 3D Sprocket
+
+type SprocketParms struct {
+	Chain       string  // chain name (see ChainLookup)
+	NumberTeeth int     // number of sprocket teeth
+	HubDiameter float64 // hub diameter (0 for no hub)
+	HubLength   float64 // hub length (beyond the teeth)
+	Bore        float64 // bore diameter (0 for none)
+}
+
+// Sprocket3D returns a roller chain sprocket with its axis on the z-axis.
+// The teeth are centered on the z = 0 plane and the hub is on the +z side.
+func Sprocket3D(k *SprocketParms) SDF3 {
+	chain := ChainLookup(k.Chain)
+	w := chain.ToothWidth()
+	s := Extrude3D(Sprocket2D(chain, k.NumberTeeth), w)
+	// chamfer the sides of the teeth to guide the chain
+	ra := 0.5 * chain.TipDiameter(k.NumberTeeth)
+	ba := 0.13 * chain.Pitch
+	bz := Min(ba, 0.25*w)
+	chamfer := Revolve3D(Polygon2D([]V2{
+		{0, -0.5 * w},
+		{ra - ba, -0.5 * w},
+		{ra, bz - 0.5*w},
+		{ra, 0.5*w - bz},
+		{ra - ba, 0.5 * w},
+		{0, 0.5 * w},
+	}))
+	s = Intersect3D(s, chamfer)
+	// hub
+	if k.HubDiameter > 0 && k.HubLength > 0 {
+		if 0.5*k.HubDiameter >= 0.5*chain.PitchDiameter(k.NumberTeeth)-chain.RollerDiameter {
+			panic("hub diameter is too large")
+		}
+		hub := Cylinder3D(k.HubLength+0.5*w, 0.5*k.HubDiameter, 0)
+		hub = Transform3D(hub, Translate3d(V3{0, 0, 0.5 * k.HubLength}))
+		s = Union3D(s, hub)
+	}
+	// bore
+	if k.Bore > 0 {
+		bb := s.BoundingBox()
+		h := bb.Size().Z
+		bore := Cylinder3D(h, 0.5*k.Bore, 0)
+		bore = Transform3D(bore, Translate3d(V3{0, 0, bb.Center().Z}))
+		s = Difference3D(s, bore)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
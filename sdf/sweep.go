@@ -0,0 +1,270 @@
+//-----------------------------------------------------------------------------
+/*
+
+Sweep a 2D profile along a 3D path.
+
+The path is a polyline. Smooth paths (Bezier or cubic splines) are sampled
+into polylines before sweeping.
+
+The profile is carried along the path with a rotation minimising frame,
+so it doesn't spin about the path as the path curves. Each segment of the
+path is a prism cut by the bisecting (mitre) planes at its end points.
+With a rotation minimising frame the profiles on either side of a mitre
+plane match up exactly.
+
+The profile x/y axes map to the frame u/v axes. The initial u axis is the
+world x-axis (or y-axis if the path starts close to x) made perpendicular
+to the starting direction of the path.
+
+The profile should be small compared to the radius of curvature of the path.
+
+*/
+//-----------------------------------------------------------------------------
+
+package sdf
+
+import "math"
+
+//-----------------------------------------------------------------------------
+// Sweep Paths
+
+// BezierPath3 returns a polyline for a piecewise cubic Bezier curve.
+// The control points are: p0, c0, c1, p1, c2, c3, p2, ...
+// Each cubic segment is sampled with n line segments.
+func BezierPath3(control V3Set, n int) V3Set {
+	if len(control) < 4 || (len(control)-1)%3 != 0 {
+		panic("bezier path needs 3k+1 control points")
+	}
+	if n < 1 {
+		panic("n < 1")
+	}
+	path := V3Set{control[0]}
+	for i := 0; i < len(control)-1; i += 3 {
+		p0, p1, p2, p3 := control[i], control[i+1], control[i+2], control[i+3]
+		for j := 1; j <= n; j++ {
+			t := float64(j) / float64(n)
+			u := 1 - t
+			p := p0.MulScalar(u * u * u)
+			p = p.Add(p1.MulScalar(3 * u * u * t))
+			p = p.Add(p2.MulScalar(3 * u * t * t))
+			p = p.Add(p3.MulScalar(t * t * t))
+			path = append(path, p)
+		}
+	}
+	return path
+}
+
+// SplinePath3 returns a polyline for a natural cubic spline through the knots.
+// Each spline segment is sampled with n line segments.
+func SplinePath3(knot V3Set, n int) V3Set {
+	if len(knot) < 2 {
+		panic("cubic splines need at least 2 knots")
+	}
+	if n < 1 {
+		panic("n < 1")
+	}
+	// Build and solve the tridiagonal matrices (see CubicSpline2D)
+	k := len(knot)
+	m := make([]V3, k)
+	dx := make([]float64, k)
+	dy := make([]float64, k)
+	dz := make([]float64, k)
+	for i := 1; i < k-1; i++ {
+		m[i] = V3{1, 4, 1}
+		d := knot[i+1].Sub(knot[i-1]).MulScalar(3)
+		dx[i], dy[i], dz[i] = d.X, d.Y, d.Z
+	}
+	m[0] = V3{0, 2, 1}
+	d := knot[1].Sub(knot[0]).MulScalar(3)
+	dx[0], dy[0], dz[0] = d.X, d.Y, d.Z
+	m[k-1] = V3{1, 2, 0}
+	d = knot[k-1].Sub(knot[k-2]).MulScalar(3)
+	dx[k-1], dy[k-1], dz[k-1] = d.X, d.Y, d.Z
+	xx := TriDiagonal(m, dx)
+	xy := TriDiagonal(m, dy)
+	xz := TriDiagonal(m, dz)
+	// sample the splines
+	path := V3Set{knot[0]}
+	for i := 0; i < k-1; i++ {
+		var px, py, pz CubicPolynomial
+		px.Set(knot[i].X, knot[i+1].X, xx[i], xx[i+1])
+		py.Set(knot[i].Y, knot[i+1].Y, xy[i], xy[i+1])
+		pz.Set(knot[i].Z, knot[i+1].Z, xz[i], xz[i+1])
+		for j := 1; j <= n; j++ {
+			t := float64(j) / float64(n)
+			path = append(path, V3{px.f0(t), py.f0(t), pz.f0(t)})
+		}
+	}
+	return path
+}
+
+//-----------------------------------------------------------------------------
+
+// sweep_segment is a single straight segment of a sweep path.
+type sweep_segment struct {
+	a    V3      // start point
+	n0   V3      // normal of the start mitre plane (points into the segment)
+	n1   V3      // normal of the end mitre plane (points out of the segment)
+	t    V3      // unit tangent
+	u, v V3      // frame vectors
+	s    float64 // path length at the start of the segment
+	l    float64 // segment length
+}
+
+type SweepSDF3 struct {
+	profile SDF2
+	segment []sweep_segment
+	length  float64 // total path length
+	twist   float64 // total twist over the path length
+	scale   float64 // profile scale at the end of the path
+	k       float64 // distance correction factor
+	bb      Box3
+}
+
+// reflect v through the plane with normal n.
+func reflect3(v, n V3) V3 {
+	return v.Sub(n.MulScalar(2 * v.Dot(n) / n.Dot(n)))
+}
+
+// Sweep3D sweeps a 2D profile along a 3D polyline.
+func Sweep3D(profile SDF2, path V3Set) SDF3 {
+	return ScaleTwistSweep3D(profile, path, 0, 1)
+}
+
+// ScaleTwistSweep3D sweeps a 2D profile along a 3D polyline.
+// The profile is rotated by twist radians and scaled from 1 to scale
+// over the length of the path.
+func ScaleTwistSweep3D(profile SDF2, path V3Set, twist, scale float64) SDF3 {
+	if scale <= 0 {
+		panic("scale <= 0")
+	}
+	// remove any repeated points
+	var p V3Set
+	for _, x := range path {
+		if len(p) == 0 || x.Sub(p[len(p)-1]).Length() > EPSILON {
+			p = append(p, x)
+		}
+	}
+	if len(p) < 2 {
+		panic("sweep path needs at least 2 distinct points")
+	}
+	s := SweepSDF3{}
+	s.profile = profile
+	s.twist = twist
+	s.scale = scale
+	n := len(p) - 1
+	s.segment = make([]sweep_segment, n)
+	// tangents and lengths
+	for i := range s.segment {
+		d := p[i+1].Sub(p[i])
+		s.segment[i].a = p[i]
+		s.segment[i].l = d.Length()
+		s.segment[i].t = d.DivScalar(s.segment[i].l)
+		s.segment[i].s = s.length
+		s.length += s.segment[i].l
+	}
+	// mitre planes
+	s.segment[0].n0 = s.segment[0].t
+	s.segment[n-1].n1 = s.segment[n-1].t
+	for i := 1; i < n; i++ {
+		t0 := s.segment[i-1].t
+		t1 := s.segment[i].t
+		m := t0.Add(t1)
+		if m.Length() < TOLERANCE {
+			panic("sweep path reverses direction")
+		}
+		m = m.Normalize()
+		s.segment[i-1].n1 = m
+		s.segment[i].n0 = m
+	}
+	// rotation minimising frame (double reflection)
+	f := axis_frame(s.segment[0].t)
+	s.segment[0].u = V3{f.x00, f.x01, f.x02}
+	s.segment[0].v = V3{f.x10, f.x11, f.x12}
+	for i := 1; i < n; i++ {
+		t0 := s.segment[i-1].t
+		t1 := s.segment[i].t
+		// the first reflection maps t0 to -t1, the second maps -t1 to t1
+		u := reflect3(reflect3(s.segment[i-1].u, t0.Add(t1)), t1)
+		s.segment[i].u = u
+		s.segment[i].v = t1.Cross(u)
+	}
+	// work out the bounding box
+	pbb := profile.BoundingBox()
+	r := 0.0
+	for _, v := range pbb.Vertices() {
+		r = Max(r, v.Length())
+	}
+	r *= Max(1, scale)
+	for i := 0; i <= n; i++ {
+		// allow for the mitre at the joints
+		k := r
+		if i > 0 && i < n {
+			k = r / s.segment[i].n0.Dot(s.segment[i].t)
+		}
+		b := Box3{p[i].SubScalar(k), p[i].AddScalar(k)}
+		if i == 0 {
+			s.bb = b
+		} else {
+			s.bb = s.bb.Extend(b)
+		}
+	}
+	// Twisting and scaling along the path stretches space.
+	t := r * (Abs(twist) + Abs(scale-1)) / s.length
+	s.k = 0.5 * (t + math.Sqrt(t*t+4)) / Min(1, scale)
+	return &s
+}
+
+// distance returns the profile distance and the mitre plane distances for a segment.
+func (s *SweepSDF3) distance(seg *sweep_segment, p V3) (d, d0, d1 float64) {
+	v := p.Sub(seg.a)
+	// distance to the mitre planes
+	d0 = -v.Dot(seg.n0)
+	d1 = v.Sub(seg.t.MulScalar(seg.l)).Dot(seg.n1)
+	// position along the path
+	x := (seg.s + Clamp(v.Dot(seg.t), 0, seg.l)) / s.length
+	// profile coordinates
+	q := V2{v.Dot(seg.u), v.Dot(seg.v)}
+	k := Mix(1, s.scale, x)
+	q = Rotate(-s.twist * x).MulPosition(q).DivScalar(k)
+	d = s.profile.Evaluate(q) * k
+	return
+}
+
+// Evaluate returns the minimum distance to a swept profile.
+func (s *SweepSDF3) Evaluate(p V3) float64 {
+	// The mitre planes divide space into slabs, one per segment.
+	// Use the segments with a slab containing p. The first and
+	// last mitre planes are the end caps of the sweep.
+	n := len(s.segment)
+	d := math.MaxFloat64
+	for i := range s.segment {
+		d2, d0, d1 := s.distance(&s.segment[i], p)
+		if i == 0 {
+			d2 = Max(d2, d0)
+		} else if d0 > 0 {
+			continue
+		}
+		if i == n-1 {
+			d2 = Max(d2, d1)
+		} else if d1 > 0 {
+			continue
+		}
+		d = Min(d, d2)
+	}
+	if d == math.MaxFloat64 {
+		// p is not within any slab, use the mitre cut segments
+		for i := range s.segment {
+			d2, d0, d1 := s.distance(&s.segment[i], p)
+			d = Min(d, Max(d2, Max(d0, d1)))
+		}
+	}
+	return d / s.k
+}
+
+// BoundingBox returns the bounding box for a swept profile.
+func (s *SweepSDF3) BoundingBox() Box3 {
+	return s.bb
+}
+
+//-----------------------------------------------------------------------------
@@ -18,6 +18,26 @@ const MM_PER_INCH = 25.4
 const TOLERANCE = 1e-9
 const EPSILON = 1e-12
 
+//-----------------------------------------------------------------------------
+// Parameter Errors
+
+// ParamError describes an invalid parameter passed to a constructor or lookup.
+type ParamError struct {
+	Func  string      // name of the function
+	Param string      // name of the offending parameter
+	Value interface{} // value of the offending parameter
+	Msg   string      // what is wrong with it
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("%s: %s = %v: %s", e.Func, e.Param, e.Value, e.Msg)
+}
+
+// param_error returns a *ParamError.
+func param_error(fn, param string, value interface{}, msg string) error {
+	return &ParamError{fn, param, value, msg}
+}
+
 //-----------------------------------------------------------------------------
 
 // Degrees to radians